@@ -0,0 +1,241 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description: 中文地址解析，将自由文本地址拆分为省、市、区县及详细地址
+
+package address
+
+import (
+	"fmt"
+	"github.com/xfali/carea"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// 参与解析的最大区域层级（区县级）
+	maxParseLevel = 3
+	// 简称最少字数，避免单字简称误匹配
+	minShortNameLength = 2
+
+	// 每个省略（由下级推断）的层级扣减的置信度
+	penaltyInferred = 0.1
+	// 每个以简称匹配的区域扣减的置信度
+	penaltyShortName = 0.05
+	// 存在同等可能的其他解析结果时置信度的折扣
+	factorAmbiguous = 0.5
+)
+
+type Result struct {
+	Province *carea.AreaData `json:"province,omitempty"`
+	City     *carea.AreaData `json:"city,omitempty"`
+	County   *carea.AreaData `json:"county,omitempty"`
+	// 区域之后的详细地址，如街道、门牌号
+	Detail string `json:"detail"`
+	// 解析置信度，取值[0, 1]
+	Confidence float64 `json:"confidence"`
+}
+
+// 解析出的最低一级区域
+func (r *Result) Area() *carea.AreaData {
+	switch {
+	case r.County != nil:
+		return r.County
+	case r.City != nil:
+		return r.City
+	default:
+		return r.Province
+	}
+}
+
+type entry struct {
+	area  carea.AreaData
+	short bool
+}
+
+type Parser struct {
+	// 名称（全称及简称） -> 区域
+	names map[string][]entry
+	// 区域Code -> 从顶级区域开始的路径
+	paths     map[carea.AreaCode][]carea.AreaData
+	maxLength int
+}
+
+// 根据区域服务创建地址解析器
+func NewParser(s carea.AreaService) (*Parser, error) {
+	ret := &Parser{
+		names: map[string][]entry{},
+		paths: map[carea.AreaCode][]carea.AreaData{},
+	}
+	for _, lv := range s.AreaLevels() {
+		if lv.Int() > maxParseLevel {
+			continue
+		}
+		areas, err := s.AreaByLevel(lv, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range areas {
+			if a.IsPlaceholder() {
+				continue
+			}
+			path, err := s.Path(a.Code)
+			if err != nil {
+				return nil, err
+			}
+			ret.paths[a.Code] = path
+			ret.add(a.Name, a.AreaData, false)
+			if short := carea.ShortName(a.Name); short != a.Name && utf8.RuneCountInString(short) >= minShortNameLength {
+				ret.add(short, a.AreaData, true)
+			}
+		}
+	}
+	return ret, nil
+}
+
+func (p *Parser) add(name string, area carea.AreaData, short bool) {
+	p.names[name] = append(p.names[name], entry{area: area, short: short})
+	if n := utf8.RuneCountInString(name); n > p.maxLength {
+		p.maxLength = n
+	}
+}
+
+type match struct {
+	entry
+	// 匹配的字节数
+	length int
+	// 匹配的字数
+	chars int
+}
+
+type candidate struct {
+	matches []match
+	score   int
+	end     int
+}
+
+// 解析地址，未匹配到任何区域时返回carea.ErrAreaNotFound
+func (p *Parser) Parse(addr string) (*Result, error) {
+	text := strings.TrimPrefix(strings.TrimSpace(addr), "中国")
+	best, ambiguous := p.search(text)
+	if len(best.matches) == 0 {
+		return &Result{Detail: text}, &carea.AreaError{
+			Err:  carea.ErrAreaNotFound,
+			Name: addr,
+		}
+	}
+
+	last := best.matches[len(best.matches)-1].area
+	ret := &Result{
+		Detail:     strings.TrimSpace(text[best.end:]),
+		Confidence: 1,
+	}
+	for _, a := range p.paths[last.Code] {
+		a := a
+		switch a.Level.Int() {
+		case 1:
+			ret.Province = &a
+		case 2:
+			ret.City = &a
+		case 3:
+			ret.County = &a
+		}
+	}
+	// 顶级区域到最低一级区域之间未在地址中出现的层级视为推断，
+	// 占位区域（如市辖区）不会出现在地址中，不计入推断层级
+	inferred := -len(best.matches)
+	for _, a := range p.paths[last.Code] {
+		if !a.IsPlaceholder() {
+			inferred++
+		}
+	}
+	ret.Confidence -= penaltyInferred * float64(inferred)
+	for _, m := range best.matches {
+		if m.short {
+			ret.Confidence -= penaltyShortName
+		}
+	}
+	if ambiguous {
+		ret.Confidence *= factorAmbiguous
+	}
+	if ret.Confidence < 0 {
+		ret.Confidence = 0
+	}
+	return ret, nil
+}
+
+// 搜索得分最高的区域匹配序列，每个区域须紧接上一个区域且为其下级区域
+func (p *Parser) search(text string) (candidate, bool) {
+	var best candidate
+	ambiguous := false
+	var walk func(c candidate)
+	walk = func(c candidate) {
+		// 得分相同时保留先找到的结果（层级较高、Code较小），使解析结果稳定
+		if c.score > best.score {
+			best, ambiguous = c, false
+		} else if c.score == best.score && c.score > 0 && !sameArea(c, best) {
+			ambiguous = true
+		}
+		for _, m := range p.prefixMatches(text[c.end:]) {
+			if len(c.matches) > 0 && !p.isDescendant(m.area, c.matches[len(c.matches)-1].area) {
+				continue
+			}
+			next := candidate{
+				matches: append(append([]match{}, c.matches...), m),
+				score:   c.score + m.score(),
+				end:     c.end + m.length,
+			}
+			walk(next)
+		}
+	}
+	walk(candidate{})
+	return best, ambiguous
+}
+
+func sameArea(a, b candidate) bool {
+	if len(a.matches) == 0 || len(b.matches) == 0 {
+		return len(a.matches) == len(b.matches)
+	}
+	return a.matches[len(a.matches)-1].area.Code == b.matches[len(b.matches)-1].area.Code
+}
+
+// 匹配得分：匹配字数，全称额外加1分
+func (m match) score() int {
+	if m.short {
+		return m.chars * 2
+	}
+	return m.chars*2 + 1
+}
+
+func (p *Parser) prefixMatches(text string) []match {
+	var ret []match
+	end := 0
+	for n := 1; n <= p.maxLength && end < len(text); n++ {
+		_, size := utf8.DecodeRuneInString(text[end:])
+		end += size
+		for _, e := range p.names[text[:end]] {
+			ret = append(ret, match{entry: e, length: end, chars: n})
+		}
+	}
+	return ret
+}
+
+func (p *Parser) isDescendant(area, ancestor carea.AreaData) bool {
+	path := p.paths[area.Code]
+	for _, a := range path[:len(path)-1] {
+		if a.Code == ancestor.Code {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Result) String() string {
+	name := func(a *carea.AreaData) string {
+		if a == nil {
+			return "-"
+		}
+		return fmt.Sprintf("%s(%s)", a.Name, a.Code)
+	}
+	return fmt.Sprintf("%s %s %s | %s (%.2f)", name(r.Province), name(r.City), name(r.County), r.Detail, r.Confidence)
+}
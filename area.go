@@ -7,6 +7,7 @@ package carea
 
 import (
 	"encoding/json"
+	"fmt"
 	"strconv"
 )
 
@@ -20,6 +21,11 @@ type AreaData struct {
 	Code       AreaCode  `json:"code"`
 	ParentCode AreaCode  `json:"parentCode"`
 	Level      AreaLevel `json:"level"`
+
+	// 名称全拼，加载时根据Name生成
+	Pinyin string `json:"pinyin,omitempty"`
+	// 名称拼音首字母，加载时根据Name生成
+	PinyinInitials string `json:"pinyinInitials,omitempty"`
 }
 
 type Area struct {
@@ -32,11 +38,27 @@ func (a Area) String() string {
 	return string(d)
 }
 
+// 层级数值，无法解析时返回0，需要错误信息请使用ParseInt
 func (lv AreaLevel) Int() int {
 	ret, _ := strconv.Atoi(string(lv))
 	return int(ret)
 }
 
+// 解析层级数值，层级必须为TopLevelInt到MaxLevelInt之间的整数
+func (lv AreaLevel) ParseInt() (int, error) {
+	ret, err := strconv.Atoi(string(lv))
+	if err != nil {
+		return 0, fmt.Errorf("level %q is not numeric", string(lv))
+	}
+	if ret < TopLevelInt {
+		return 0, fmt.Errorf("level %d less than %d", ret, TopLevelInt)
+	}
+	if ret > MaxLevelInt {
+		return 0, fmt.Errorf("level %d greater than %d", ret, MaxLevelInt)
+	}
+	return ret, nil
+}
+
 func String2AreaCode(code string) AreaCode {
 	return AreaCode(code)
 }
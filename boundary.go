@@ -0,0 +1,504 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description: 区域边界多边形加载（GeoJSON/TopoJSON）及点在多边形内判断
+
+package carea
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"strings"
+)
+
+// 边界数据中区域Code的属性名，按顺序查找，均不存在时使用Feature的id
+var boundaryCodeKeys = []string{"code", "adcode", "areaCode"}
+
+// 多边形的环，首尾点可以相同
+type Ring []Coordinate
+
+// 多边形，第一个环为外边界，其余环为内部空洞
+type Polygon []Ring
+
+// 区域边界，可由多个多边形组成（如包含岛屿的区域）
+type Boundary struct {
+	Polygons []Polygon
+	// 外接矩形
+	MinLatitude  float64
+	MinLongitude float64
+	MaxLatitude  float64
+	MaxLongitude float64
+}
+
+type BoundarySource func() (map[AreaCode]*Boundary, error)
+
+// 设置边界数据来源
+func (opt defaultOption) SetBoundarySource(bs BoundarySource) Opt {
+	return func(s *defaultAreaService) {
+		s.bs = bs
+	}
+}
+
+// 从GeoJSON或TopoJSON文件加载边界数据，见LoadBoundaries
+func (opt defaultOption) LoadBoundaryFromFile(path string) Opt {
+	return func(s *defaultAreaService) {
+		s.bs = func() (map[AreaCode]*Boundary, error) {
+			f, err := os.Open(path)
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+			return LoadBoundaries(f)
+		}
+	}
+}
+
+// 解析GeoJSON（FeatureCollection或Feature）或TopoJSON（Topology）格式的边界数据，
+// 区域Code取自属性code、adcode或areaCode，均不存在时取自Feature的id，无法取得Code的Feature被忽略。
+// 仅解析Polygon与MultiPolygon几何，同一Code的多个Feature合并为一个边界
+func LoadBoundaries(r io.Reader) (map[AreaCode]*Boundary, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var doc geoDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, invalidDataError(err)
+	}
+	ret := map[AreaCode]*Boundary{}
+	switch doc.Type {
+	case "FeatureCollection":
+		for _, f := range doc.Features {
+			if err := addGeoJSONFeature(ret, f); err != nil {
+				return nil, err
+			}
+		}
+	case "Feature":
+		var f geoFeature
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, invalidDataError(err)
+		}
+		if err := addGeoJSONFeature(ret, f); err != nil {
+			return nil, err
+		}
+	case "Topology":
+		topo := newTopology(doc)
+		for _, obj := range doc.Objects {
+			if err := topo.addObject(ret, obj); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, invalidDataError(fmt.Errorf("unsupported boundary type %q", doc.Type))
+	}
+	for _, b := range ret {
+		b.computeBounds()
+	}
+	return ret, nil
+}
+
+// 点是否在边界内（含空洞的多边形按奇偶规则判断）
+func (b *Boundary) Contains(lat, lng float64) bool {
+	if lat < b.MinLatitude || lat > b.MaxLatitude || lng < b.MinLongitude || lng > b.MaxLongitude {
+		return false
+	}
+	for _, p := range b.Polygons {
+		if p.contains(lat, lng) {
+			return true
+		}
+	}
+	return false
+}
+
+// 边界的球面面积，单位平方公里
+func (b *Boundary) Area() float64 {
+	total := 0.0
+	for _, p := range b.Polygons {
+		for i, ring := range p {
+			if i == 0 {
+				total += ring.area()
+			} else {
+				total -= ring.area()
+			}
+		}
+	}
+	return total
+}
+
+func (b *Boundary) computeBounds() {
+	b.MinLatitude, b.MinLongitude = math.Inf(1), math.Inf(1)
+	b.MaxLatitude, b.MaxLongitude = math.Inf(-1), math.Inf(-1)
+	for _, p := range b.Polygons {
+		if len(p) == 0 {
+			continue
+		}
+		for _, c := range p[0] {
+			b.MinLatitude = math.Min(b.MinLatitude, c.Latitude)
+			b.MaxLatitude = math.Max(b.MaxLatitude, c.Latitude)
+			b.MinLongitude = math.Min(b.MinLongitude, c.Longitude)
+			b.MaxLongitude = math.Max(b.MaxLongitude, c.Longitude)
+		}
+	}
+}
+
+func (p Polygon) contains(lat, lng float64) bool {
+	in := false
+	for _, ring := range p {
+		if ring.crossings(lat, lng)%2 == 1 {
+			in = !in
+		}
+	}
+	return in
+}
+
+// 从点向经度正方向的射线与环的交点数
+func (r Ring) crossings(lat, lng float64) int {
+	n := 0
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		a, b := r[i], r[j]
+		if (a.Latitude > lat) != (b.Latitude > lat) {
+			x := a.Longitude + (lat-a.Latitude)/(b.Latitude-a.Latitude)*(b.Longitude-a.Longitude)
+			if lng < x {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// 环的球面面积，单位平方公里
+func (r Ring) area() float64 {
+	if len(r) < 3 {
+		return 0
+	}
+	rad := math.Pi / 180
+	sum := 0.0
+	for i := range r {
+		a, b := r[i], r[(i+1)%len(r)]
+		sum += (b.Longitude - a.Longitude) * rad * (2 + math.Sin(a.Latitude*rad) + math.Sin(b.Latitude*rad))
+	}
+	return math.Abs(sum) * earthRadiusKm * earthRadiusKm / 2
+}
+
+// 加载边界数据并转换为区域服务的坐标系，边界数据的坐标系与区域数据相同，忽略数据中不存在的区域Code
+func (s *defaultAreaService) loadBoundaries() error {
+	if s.bs == nil {
+		return nil
+	}
+	bs, err := s.bs()
+	if err != nil {
+		return err
+	}
+	s.boundaries = make(map[AreaCode]*Boundary, len(bs))
+	s.boundaryLevels = make([][]AreaData, len(s.areas))
+	for _, lv := range s.areas {
+		for _, ad := range lv {
+			if b, ok := bs[ad.Code]; ok && len(b.Polygons) > 0 {
+				s.boundaries[ad.Code] = convertBoundary(b, s.dataCoord, s.coord)
+				i := ad.Level.Int() - 1
+				s.boundaryLevels[i] = append(s.boundaryLevels[i], ad)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *defaultAreaService) Boundary(code AreaCode) (*Boundary, error) {
+	code = code.Normalize()
+	if _, ok := s.codeIndex[code]; !ok {
+		return nil, notFoundError(code)
+	}
+	b, ok := s.boundaries[code]
+	if !ok {
+		return nil, &AreaError{
+			Err:  ErrBoundaryNotFound,
+			Code: code,
+		}
+	}
+	return b, nil
+}
+
+func (s *defaultAreaService) Locate(lat, lng float64, opts ...SpatialOpt) ([]AreaData, error) {
+	lat, lng, err := s.inputCoordinate(lat, lng, opts)
+	if err != nil {
+		return nil, err
+	}
+	var ret []AreaData
+	for _, lv := range s.boundaryLevels {
+		var found []AreaData
+		for _, ad := range lv {
+			if s.boundaries[ad.Code].Contains(lat, lng) {
+				found = append(found, ad)
+			}
+		}
+		if len(found) == 0 {
+			continue
+		}
+		// 边界重叠时优先选择上级区域的子区域
+		match := found[0]
+		if len(ret) > 0 {
+			for _, ad := range found {
+				if s.isDescendant(ad, ret[len(ret)-1].Code) {
+					match = ad
+					break
+				}
+			}
+		}
+		ret = append(ret, match)
+	}
+	if len(ret) == 0 {
+		return nil, &AreaError{
+			Err:   ErrAreaNotFound,
+			Cause: fmt.Errorf("no boundary contains (%v, %v)", lat, lng),
+		}
+	}
+	return ret, nil
+}
+
+func (s *defaultAreaService) Contains(code AreaCode, lat, lng float64, opts ...SpatialOpt) (bool, error) {
+	lat, lng, err := s.inputCoordinate(lat, lng, opts)
+	if err != nil {
+		return false, err
+	}
+	b, err := s.Boundary(code)
+	if err != nil {
+		return false, err
+	}
+	return b.Contains(lat, lng), nil
+}
+
+func (s *defaultAreaService) PolygonArea(code AreaCode) (float64, error) {
+	b, err := s.Boundary(code)
+	if err != nil {
+		return 0, err
+	}
+	return b.Area(), nil
+}
+
+type geoDocument struct {
+	Type     string       `json:"type"`
+	Features []geoFeature `json:"features"`
+	// TopoJSON
+	Transform *struct {
+		Scale     [2]float64 `json:"scale"`
+		Translate [2]float64 `json:"translate"`
+	} `json:"transform"`
+	Arcs    [][][]float64           `json:"arcs"`
+	Objects map[string]topoGeometry `json:"objects"`
+}
+
+type geoFeature struct {
+	ID         interface{}            `json:"id"`
+	Properties map[string]interface{} `json:"properties"`
+	Geometry   *struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	} `json:"geometry"`
+}
+
+type topoGeometry struct {
+	Type       string                 `json:"type"`
+	ID         interface{}            `json:"id"`
+	Properties map[string]interface{} `json:"properties"`
+	Arcs       json.RawMessage        `json:"arcs"`
+	Geometries []topoGeometry         `json:"geometries"`
+}
+
+// 从属性或id中取得区域Code
+func featureCode(id interface{}, props map[string]interface{}) AreaCode {
+	for _, k := range boundaryCodeKeys {
+		if c := codeValue(props[k]); c != "" {
+			return c
+		}
+	}
+	return codeValue(id)
+}
+
+func codeValue(v interface{}) AreaCode {
+	switch c := v.(type) {
+	case string:
+		c = strings.TrimSpace(c)
+		if isDigits(c) {
+			return AreaCode(c).Normalize()
+		}
+	case float64:
+		if c > 0 && c == math.Trunc(c) {
+			return AreaCode(fmt.Sprintf("%.0f", c)).Normalize()
+		}
+	}
+	return ""
+}
+
+func addPolygons(m map[AreaCode]*Boundary, code AreaCode, polygons []Polygon) {
+	b, ok := m[code]
+	if !ok {
+		b = &Boundary{}
+		m[code] = b
+	}
+	b.Polygons = append(b.Polygons, polygons...)
+}
+
+func addGeoJSONFeature(m map[AreaCode]*Boundary, f geoFeature) error {
+	code := featureCode(f.ID, f.Properties)
+	if code == "" || f.Geometry == nil {
+		return nil
+	}
+	var polygons []Polygon
+	switch f.Geometry.Type {
+	case "Polygon":
+		var coords [][][]float64
+		if err := json.Unmarshal(f.Geometry.Coordinates, &coords); err != nil {
+			return boundaryError(code, err)
+		}
+		polygons = append(polygons, toPolygon(coords))
+	case "MultiPolygon":
+		var coords [][][][]float64
+		if err := json.Unmarshal(f.Geometry.Coordinates, &coords); err != nil {
+			return boundaryError(code, err)
+		}
+		for _, c := range coords {
+			polygons = append(polygons, toPolygon(c))
+		}
+	default:
+		return nil
+	}
+	addPolygons(m, code, polygons)
+	return nil
+}
+
+// GeoJSON坐标顺序为[经度, 纬度]
+func toPolygon(coords [][][]float64) Polygon {
+	ret := make(Polygon, 0, len(coords))
+	for _, ring := range coords {
+		r := make(Ring, 0, len(ring))
+		for _, pos := range ring {
+			if len(pos) < 2 {
+				continue
+			}
+			r = append(r, Coordinate{Latitude: pos[1], Longitude: pos[0]})
+		}
+		ret = append(ret, r)
+	}
+	return ret
+}
+
+type topology struct {
+	arcs [][]Coordinate
+}
+
+// 解码TopoJSON的arcs，存在transform时坐标为量化后的差分编码
+func newTopology(doc geoDocument) *topology {
+	ret := &topology{
+		arcs: make([][]Coordinate, len(doc.Arcs)),
+	}
+	for i, arc := range doc.Arcs {
+		coords := make([]Coordinate, 0, len(arc))
+		x, y := 0.0, 0.0
+		for _, pos := range arc {
+			if len(pos) < 2 {
+				continue
+			}
+			if doc.Transform != nil {
+				x += pos[0]
+				y += pos[1]
+				coords = append(coords, Coordinate{
+					Latitude:  y*doc.Transform.Scale[1] + doc.Transform.Translate[1],
+					Longitude: x*doc.Transform.Scale[0] + doc.Transform.Translate[0],
+				})
+			} else {
+				coords = append(coords, Coordinate{Latitude: pos[1], Longitude: pos[0]})
+			}
+		}
+		ret.arcs[i] = coords
+	}
+	return ret
+}
+
+func (t *topology) addObject(m map[AreaCode]*Boundary, g topoGeometry) error {
+	if g.Type == "GeometryCollection" {
+		for _, sub := range g.Geometries {
+			if err := t.addObject(m, sub); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	code := featureCode(g.ID, g.Properties)
+	if code == "" {
+		return nil
+	}
+	var polygons []Polygon
+	switch g.Type {
+	case "Polygon":
+		var arcs [][]int
+		if err := json.Unmarshal(g.Arcs, &arcs); err != nil {
+			return boundaryError(code, err)
+		}
+		p, err := t.polygon(arcs)
+		if err != nil {
+			return boundaryError(code, err)
+		}
+		polygons = append(polygons, p)
+	case "MultiPolygon":
+		var arcs [][][]int
+		if err := json.Unmarshal(g.Arcs, &arcs); err != nil {
+			return boundaryError(code, err)
+		}
+		for _, a := range arcs {
+			p, err := t.polygon(a)
+			if err != nil {
+				return boundaryError(code, err)
+			}
+			polygons = append(polygons, p)
+		}
+	default:
+		return nil
+	}
+	addPolygons(m, code, polygons)
+	return nil
+}
+
+func (t *topology) polygon(rings [][]int) (Polygon, error) {
+	ret := make(Polygon, 0, len(rings))
+	for _, indexes := range rings {
+		var ring Ring
+		for _, i := range indexes {
+			// 负数索引~i表示反向使用第i条arc
+			reverse := i < 0
+			if reverse {
+				i = ^i
+			}
+			if i >= len(t.arcs) {
+				return nil, fmt.Errorf("arc index %d out of range", i)
+			}
+			arc := t.arcs[i]
+			points := make([]Coordinate, len(arc))
+			for j := range arc {
+				if reverse {
+					points[j] = arc[len(arc)-1-j]
+				} else {
+					points[j] = arc[j]
+				}
+			}
+			// 相邻arc首尾点重合
+			if len(ring) > 0 && len(points) > 0 {
+				points = points[1:]
+			}
+			ring = append(ring, points...)
+		}
+		ret = append(ret, ring)
+	}
+	return ret, nil
+}
+
+func boundaryError(code AreaCode, cause error) error {
+	return &AreaError{
+		Err:   ErrInvalidData,
+		Code:  code,
+		Cause: cause,
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description: 比较两个区域数据JSON文件的差异
+// 用法：careadiff [-format text|json] OLD.json NEW.json
+// 无差异时退出码为0，存在差异时为1，出错时为2
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/xfali/carea"
+	"os"
+)
+
+func main() {
+	format := flag.String("format", "text", "output format: text or json")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-format text|json] OLD.json NEW.json\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 || (*format != "text" && *format != "json") {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	report, err := diffFiles(flag.Arg(0), flag.Arg(1))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	} else {
+		fmt.Println(report)
+	}
+	if !report.IsEmpty() {
+		os.Exit(1)
+	}
+}
+
+func diffFiles(oldPath, newPath string) (*carea.DiffReport, error) {
+	oldService, err := carea.NewAreaServiceE(carea.DefaultOpt.LoadFromFile(oldPath))
+	if err != nil {
+		return nil, fmt.Errorf("load %s failed: %w", oldPath, err)
+	}
+	newService, err := carea.NewAreaServiceE(carea.DefaultOpt.LoadFromFile(newPath))
+	if err != nil {
+		return nil, fmt.Errorf("load %s failed: %w", newPath, err)
+	}
+	return carea.Diff(oldService, newService)
+}
@@ -0,0 +1,217 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description: GB/T 2260 行政区划代码结构：
+// 前2位为省级代码，3-4位为地级代码，5-6位为县级代码；
+// 国家统计局统计用区划代码在此基础上以7-9位为乡级代码，10-12位为村级代码
+
+package carea
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	// 区划代码长度（省、地、县级）
+	AreaCodeLength = 6
+	// 乡级区划代码长度
+	TownshipCodeLength = 9
+	// 统计用区划代码长度（村级）
+	StatCodeLength = 12
+
+	provinceSegment   = 2
+	prefectureSegment = 4
+)
+
+const (
+	ProvinceLevel   = TopLevel
+	PrefectureLevel = AreaLevel("2")
+	CountyLevel     = AreaLevel("3")
+	TownshipLevel   = AreaLevel("4")
+	VillageLevel    = AreaLevel("5")
+)
+
+// 直辖市省级代码
+var municipalities = map[string]bool{
+	"11": true, // 北京市
+	"12": true, // 天津市
+	"31": true, // 上海市
+	"50": true, // 重庆市
+}
+
+// 规范化区划代码：12位统计用区划代码去掉末尾为0的乡级、村级段，
+// 即县级及以上返回6位代码，乡级返回9位代码，村级保持12位；其他代码原样返回
+func (c AreaCode) Normalize() AreaCode {
+	if len(c) != StatCodeLength || !isDigits(string(c)) {
+		return c
+	}
+	if c[AreaCodeLength:] == "000000" {
+		return c[:AreaCodeLength]
+	}
+	if c[TownshipCodeLength:] == "000" {
+		return c[:TownshipCodeLength]
+	}
+	return c
+}
+
+// 转换为12位统计用区划代码，格式不合法时返回空
+func (c AreaCode) StatCode() AreaCode {
+	n := c.Normalize()
+	if !n.IsValidFormat() {
+		return ""
+	}
+	return n + AreaCode(strings.Repeat("0", StatCodeLength-len(n)))
+}
+
+// 是否为结构合法的6位、9位或12位数字代码：
+// 省级代码不为00，地级代码为00时县级代码也必须为00，乡级、村级代码不为000
+func (c AreaCode) IsValidFormat() bool {
+	c = c.Normalize()
+	if !isDigits(string(c)) {
+		return false
+	}
+	switch len(c) {
+	case AreaCodeLength:
+		if c[:provinceSegment] == "00" {
+			return false
+		}
+		if c[provinceSegment:prefectureSegment] == "00" && c[prefectureSegment:] != "00" {
+			return false
+		}
+		return true
+	case TownshipCodeLength:
+		// 乡级可直属于不设区县的地级市，如东莞市
+		return c[AreaCodeLength:] != "000" && c[:AreaCodeLength].InferredLevel().Int() > 1
+	case StatCodeLength:
+		return c[:TownshipCodeLength].IsValidFormat()
+	}
+	return false
+}
+
+// 省级区划代码，格式不合法时返回空
+func (c AreaCode) ProvinceCode() AreaCode {
+	if !c.IsValidFormat() {
+		return ""
+	}
+	return c[:provinceSegment] + "0000"
+}
+
+// 地级区划代码，格式不合法或为省级代码时返回空
+func (c AreaCode) PrefectureCode() AreaCode {
+	if c.InferredLevel().Int() < 2 {
+		return ""
+	}
+	return c[:prefectureSegment] + "00"
+}
+
+// 县级区划代码，格式不合法或层级高于县级时返回空
+func (c AreaCode) CountyCode() AreaCode {
+	if c.InferredLevel().Int() < 3 {
+		return ""
+	}
+	return c[:AreaCodeLength]
+}
+
+// 乡级区划代码，格式不合法或层级高于乡级时返回空
+func (c AreaCode) TownshipCode() AreaCode {
+	if c.InferredLevel().Int() < 4 {
+		return ""
+	}
+	return c.Normalize()[:TownshipCodeLength]
+}
+
+// 根据代码结构推断的层级：1为省级，2为地级，3为县级，4为乡级，5为村级，格式不合法时返回空
+// 注意直属于地级市的乡级代码前6位为地级代码，其层级仍为乡级
+func (c AreaCode) InferredLevel() AreaLevel {
+	if !c.IsValidFormat() {
+		return ""
+	}
+	c = c.Normalize()
+	switch {
+	case len(c) == StatCodeLength:
+		return VillageLevel
+	case len(c) == TownshipCodeLength:
+		return TownshipLevel
+	case c[provinceSegment:] == "0000":
+		return ProvinceLevel
+	case c[prefectureSegment:] == "00":
+		return PrefectureLevel
+	default:
+		return CountyLevel
+	}
+}
+
+// 根据代码结构推断的父区域代码，省级代码返回RootParentCode，格式不合法时返回空
+func (c AreaCode) InferredParentCode() AreaCode {
+	switch c.InferredLevel() {
+	case ProvinceLevel:
+		return RootParentCode
+	case PrefectureLevel:
+		return c.ProvinceCode()
+	case CountyLevel:
+		return c.PrefectureCode()
+	case TownshipLevel:
+		return c.Normalize()[:AreaCodeLength]
+	case VillageLevel:
+		return c.TownshipCode()
+	}
+	return ""
+}
+
+// 是否为直辖市（北京、天津、上海、重庆）的省级代码
+func (c AreaCode) IsMunicipality() bool {
+	return c.InferredLevel() == ProvinceLevel && municipalities[string(c[:provinceSegment])]
+}
+
+// 是否为直辖市下辖的县级区划代码（区、县）
+func (c AreaCode) IsMunicipalityDistrict() bool {
+	return c.InferredLevel() == CountyLevel && municipalities[string(c[:provinceSegment])]
+}
+
+// 查找区划代码对应的区域，不存在时（如已撤销的代码）依次查找其变更后的代码（见AreaService.ResolveCode）、
+// 县级、地级、省级区域，exact表示返回的区域是否即为code对应的区域，均不存在时返回ErrAreaNotFound
+func AreaOrAncestor(s AreaService, code AreaCode) (area AreaData, exact bool, err error) {
+	code = code.Normalize()
+	candidates := []AreaCode{code}
+	if resolved, err := s.ResolveCode(code); err == nil {
+		candidates = append(candidates, resolved...)
+	}
+	candidates = append(candidates, code.CountyCode(), code.PrefectureCode(), code.ProvinceCode())
+	for _, c := range candidates {
+		if c == "" {
+			continue
+		}
+		a, err := s.AreaByCode(c, false)
+		if err == nil {
+			return a.AreaData, c == code, nil
+		}
+		if !errors.Is(err, ErrAreaNotFound) {
+			return AreaData{}, false, err
+		}
+	}
+	return AreaData{}, false, notFoundError(code)
+}
+
+// 复制数据并规范化其中的Code与ParentCode
+func normalizeData(data []AreaData) []AreaData {
+	ret := make([]AreaData, len(data))
+	for i, ad := range data {
+		ad.Code = ad.Code.Normalize()
+		ad.ParentCode = ad.ParentCode.Normalize()
+		ret[i] = ad
+	}
+	return ret
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,103 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description:
+
+package carea
+
+import (
+	"math"
+	"strconv"
+)
+
+const earthRadiusKm = 6371.0088
+
+// 经纬度坐标
+type Coordinate struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// 解析区域坐标，坐标缺失、无法解析或为NaN、Inf时ok为false
+func (a AreaData) Location() (lat, lng float64, ok bool) {
+	lat, err := strconv.ParseFloat(a.Latitude, 64)
+	if err != nil || !isFinite(lat) {
+		return 0, 0, false
+	}
+	lng, err = strconv.ParseFloat(a.Longitude, 64)
+	if err != nil || !isFinite(lng) {
+		return 0, 0, false
+	}
+	return lat, lng, true
+}
+
+func isFinite(f float64) bool {
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// 区域坐标，坐标缺失或无法解析时ok为false
+func (a AreaData) Coordinate() (Coordinate, bool) {
+	lat, lng, ok := a.Location()
+	return Coordinate{Latitude: lat, Longitude: lng}, ok
+}
+
+// 是否有可用坐标
+func (a AreaData) HasLocation() bool {
+	_, _, ok := a.Location()
+	return ok
+}
+
+func (a *AreaData) setLocation(lat, lng float64) {
+	a.Latitude = strconv.FormatFloat(lat, 'f', 6, 64)
+	a.Longitude = strconv.FormatFloat(lng, 'f', 6, 64)
+}
+
+// 以子区域坐标的平均值填充缺失坐标的区域，子区域同样缺失坐标时先递归计算子区域，suspect中的子区域不参与计算
+func fillCentroids(data []AreaData, suspect map[AreaCode]bool) {
+	children := make(map[AreaCode][]int, len(data)/8)
+	for i := range data {
+		children[data[i].ParentCode] = append(children[data[i].ParentCode], i)
+	}
+	done := make([]bool, len(data))
+	var fill func(i int) bool
+	fill = func(i int) bool {
+		if done[i] {
+			return data[i].HasLocation()
+		}
+		done[i] = true
+		if data[i].HasLocation() {
+			return true
+		}
+		var sumLat, sumLng float64
+		n := 0
+		for _, c := range children[data[i].Code] {
+			if suspect[data[c].Code] {
+				continue
+			}
+			if fill(c) {
+				lat, lng, _ := data[c].Location()
+				sumLat += lat
+				sumLng += lng
+				n++
+			}
+		}
+		if n == 0 {
+			return false
+		}
+		data[i].setLocation(sumLat/float64(n), sumLng/float64(n))
+		return true
+	}
+	for i := range data {
+		fill(i)
+	}
+}
+
+// 两点间球面距离，单位公里
+func haversine(lat1, lng1, lat2, lng2 float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLng := (lng2 - lng1) * rad
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
@@ -0,0 +1,184 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description: WGS-84、GCJ-02、BD-09坐标系转换
+
+package carea
+
+import (
+	"fmt"
+	"github.com/xfali/carea/static"
+	"math"
+)
+
+type CoordSystem string
+
+const (
+	// GPS使用的国际标准坐标系
+	WGS84 = CoordSystem("WGS-84")
+	// 国测局坐标系，高德、腾讯地图使用
+	GCJ02 = CoordSystem("GCJ-02")
+	// 百度坐标系
+	BD09 = CoordSystem("BD-09")
+
+	// 内置数据的坐标系
+	BuildinCoordSystem = CoordSystem(static.AreasCoordSystem)
+)
+
+const (
+	// 克拉索夫斯基椭球参数
+	krasovskyA  = 6378245.0
+	krasovskyEE = 0.00669342162296594323
+	bdXPi       = math.Pi * 3000.0 / 180.0
+	// GCJ-02逆转换的迭代精度，单位度
+	gcjInverseEpsilon = 1e-9
+)
+
+func (c CoordSystem) IsValid() bool {
+	return c == WGS84 || c == GCJ02 || c == BD09
+}
+
+// 坐标系转换，坐标系无效时返回ErrInvalidCoordinate
+func ConvertCoordinate(lat, lng float64, from, to CoordSystem) (float64, float64, error) {
+	if !from.IsValid() || !to.IsValid() {
+		return lat, lng, &AreaError{
+			Err:   ErrInvalidCoordinate,
+			Cause: fmt.Errorf("unsupported coordinate system conversion %s -> %s", from, to),
+		}
+	}
+	if from == to {
+		return lat, lng, nil
+	}
+	switch from {
+	case WGS84:
+		lat, lng = WGS84ToGCJ02(lat, lng)
+	case BD09:
+		lat, lng = BD09ToGCJ02(lat, lng)
+	}
+	switch to {
+	case WGS84:
+		lat, lng = GCJ02ToWGS84(lat, lng)
+	case BD09:
+		lat, lng = GCJ02ToBD09(lat, lng)
+	}
+	return lat, lng, nil
+}
+
+// 坐标系转换，见ConvertCoordinate
+func (c Coordinate) Convert(from, to CoordSystem) (Coordinate, error) {
+	lat, lng, err := ConvertCoordinate(c.Latitude, c.Longitude, from, to)
+	return Coordinate{Latitude: lat, Longitude: lng}, err
+}
+
+// WGS-84转GCJ-02，中国境外的坐标不做偏移
+func WGS84ToGCJ02(lat, lng float64) (float64, float64) {
+	if outOfChina(lat, lng) {
+		return lat, lng
+	}
+	dLat, dLng := gcjDelta(lat, lng)
+	return lat + dLat, lng + dLng
+}
+
+// GCJ-02转WGS-84，迭代求解，精度优于1e-9度
+func GCJ02ToWGS84(lat, lng float64) (float64, float64) {
+	if outOfChina(lat, lng) {
+		return lat, lng
+	}
+	wLat, wLng := lat, lng
+	for i := 0; i < 30; i++ {
+		gLat, gLng := WGS84ToGCJ02(wLat, wLng)
+		dLat, dLng := gLat-lat, gLng-lng
+		wLat, wLng = wLat-dLat, wLng-dLng
+		if math.Abs(dLat) < gcjInverseEpsilon && math.Abs(dLng) < gcjInverseEpsilon {
+			break
+		}
+	}
+	return wLat, wLng
+}
+
+// GCJ-02转BD-09
+func GCJ02ToBD09(lat, lng float64) (float64, float64) {
+	z := math.Sqrt(lng*lng+lat*lat) + 0.00002*math.Sin(lat*bdXPi)
+	theta := math.Atan2(lat, lng) + 0.000003*math.Cos(lng*bdXPi)
+	return z*math.Sin(theta) + 0.006, z*math.Cos(theta) + 0.0065
+}
+
+// BD-09转GCJ-02
+func BD09ToGCJ02(lat, lng float64) (float64, float64) {
+	x, y := lng-0.0065, lat-0.006
+	z := math.Sqrt(x*x+y*y) - 0.00002*math.Sin(y*bdXPi)
+	theta := math.Atan2(y, x) - 0.000003*math.Cos(x*bdXPi)
+	return z * math.Sin(theta), z * math.Cos(theta)
+}
+
+// WGS-84转BD-09
+func WGS84ToBD09(lat, lng float64) (float64, float64) {
+	return GCJ02ToBD09(WGS84ToGCJ02(lat, lng))
+}
+
+// BD-09转WGS-84
+func BD09ToWGS84(lat, lng float64) (float64, float64) {
+	return GCJ02ToWGS84(BD09ToGCJ02(lat, lng))
+}
+
+// GCJ-02仅对中国范围内的坐标加偏
+func outOfChina(lat, lng float64) bool {
+	return lng < 72.004 || lng > 137.8347 || lat < 0.8293 || lat > 55.8271
+}
+
+func gcjDelta(lat, lng float64) (float64, float64) {
+	x, y := lng-105.0, lat-35.0
+	dLat := -100.0 + 2.0*x + 3.0*y + 0.2*y*y + 0.1*x*y + 0.2*math.Sqrt(math.Abs(x))
+	dLat += (20.0*math.Sin(6.0*x*math.Pi) + 20.0*math.Sin(2.0*x*math.Pi)) * 2.0 / 3.0
+	dLat += (20.0*math.Sin(y*math.Pi) + 40.0*math.Sin(y/3.0*math.Pi)) * 2.0 / 3.0
+	dLat += (160.0*math.Sin(y/12.0*math.Pi) + 320*math.Sin(y*math.Pi/30.0)) * 2.0 / 3.0
+	dLng := 300.0 + x + 2.0*y + 0.1*x*x + 0.1*x*y + 0.1*math.Sqrt(math.Abs(x))
+	dLng += (20.0*math.Sin(6.0*x*math.Pi) + 20.0*math.Sin(2.0*x*math.Pi)) * 2.0 / 3.0
+	dLng += (20.0*math.Sin(x*math.Pi) + 40.0*math.Sin(x/3.0*math.Pi)) * 2.0 / 3.0
+	dLng += (150.0*math.Sin(x/12.0*math.Pi) + 300.0*math.Sin(x/30.0*math.Pi)) * 2.0 / 3.0
+
+	radLat := lat / 180.0 * math.Pi
+	magic := math.Sin(radLat)
+	magic = 1 - krasovskyEE*magic*magic
+	sqrtMagic := math.Sqrt(magic)
+	dLat = (dLat * 180.0) / ((krasovskyA * (1 - krasovskyEE)) / (magic * sqrtMagic) * math.Pi)
+	dLng = (dLng * 180.0) / (krasovskyA / sqrtMagic * math.Cos(radLat) * math.Pi)
+	return dLat, dLng
+}
+
+// 转换数据中的区域坐标，坐标缺失的区域不变
+func convertCoordinates(data []AreaData, from, to CoordSystem) {
+	if from == to {
+		return
+	}
+	for i := range data {
+		lat, lng, ok := data[i].Location()
+		if !ok {
+			continue
+		}
+		lat, lng, _ = ConvertCoordinate(lat, lng, from, to)
+		data[i].setLocation(lat, lng)
+	}
+}
+
+// 转换边界坐标
+func convertBoundary(b *Boundary, from, to CoordSystem) *Boundary {
+	if from == to {
+		return b
+	}
+	ret := &Boundary{
+		Polygons: make([]Polygon, len(b.Polygons)),
+	}
+	for i, p := range b.Polygons {
+		ret.Polygons[i] = make(Polygon, len(p))
+		for j, ring := range p {
+			r := make(Ring, len(ring))
+			for k, c := range ring {
+				r[k], _ = c.Convert(from, to)
+			}
+			ret.Polygons[i][j] = r
+		}
+	}
+	ret.computeBounds()
+	return ret
+}
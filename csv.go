@@ -0,0 +1,447 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description: CSV/TSV格式区域数据的导入与导出
+
+package carea
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CSV列可映射的区域字段
+const (
+	CSVCode       = "code"
+	CSVParentCode = "parentCode"
+	CSVLevel      = "level"
+	CSVName       = "name"
+	CSVLatitude   = "latitude"
+	CSVLongitude  = "longitude"
+)
+
+// 无表头时的默认列顺序，也是导出时的默认列顺序
+var csvFields = []string{CSVCode, CSVParentCode, CSVLevel, CSVName, CSVLatitude, CSVLongitude}
+
+// 表头中可识别的字段名称（不区分大小写）
+var csvAliases = map[string]string{
+	"code":        CSVCode,
+	"parentcode":  CSVParentCode,
+	"parent_code": CSVParentCode,
+	"level":       CSVLevel,
+	"name":        CSVName,
+	"latitude":    CSVLatitude,
+	"lat":         CSVLatitude,
+	"longitude":   CSVLongitude,
+	"lng":         CSVLongitude,
+	"lon":         CSVLongitude,
+}
+
+const (
+	csvHeaderAuto = iota
+	csvHeaderYes
+	csvHeaderNo
+)
+
+// CSV/TSV的列布局及附加列，由LoadCSVData返回，写出时按原有的列顺序、表头及分隔符还原
+type CSVLayout struct {
+	// 各列对应的区域字段（CSVCode等）或附加列名称，按原有顺序
+	Columns []string
+	// 原有表头，无表头时为nil
+	Header []string
+	Comma  rune
+	// 按区域Code保存的附加列：列名 -> 值，Code重复时以后出现的记录为准
+	Extra map[AreaCode]map[string]string
+}
+
+type csvConfig struct {
+	comma  rune
+	header int
+	layout *CSVLayout
+	// 字段 -> 表头名称
+	names map[string]string
+	// 字段 -> 列序号，无表头时使用
+	indexes map[string]int
+}
+
+type CSVOpt func(c *csvConfig)
+
+type defaultCSVOption struct{}
+
+var DefaultCSVOpt defaultCSVOption
+
+// 字段分隔符，默认为逗号
+func (opt defaultCSVOption) Comma(comma rune) CSVOpt {
+	return func(c *csvConfig) {
+		c.comma = comma
+	}
+}
+
+// 使用制表符分隔（TSV）
+func (opt defaultCSVOption) TSV() CSVOpt {
+	return opt.Comma('\t')
+}
+
+// 是否有表头，默认根据首行是否包含可识别的字段名称自动判断；导出时决定是否写出表头
+func (opt defaultCSVOption) Header(header bool) CSVOpt {
+	return func(c *csvConfig) {
+		if header {
+			c.header = csvHeaderYes
+		} else {
+			c.header = csvHeaderNo
+		}
+	}
+}
+
+// 将字段映射到指定表头名称的列，field为CSVCode、CSVName等
+func (opt defaultCSVOption) Column(field, name string) CSVOpt {
+	return func(c *csvConfig) {
+		c.names[field] = name
+	}
+}
+
+// 无表头时将字段映射到指定序号（从0开始）的列，导出时按序号排列字段
+func (opt defaultCSVOption) Index(field string, index int) CSVOpt {
+	return func(c *csvConfig) {
+		c.indexes[field] = index
+	}
+}
+
+// 写出时使用的列布局，优先于Comma、Header、Column、Index选项，见LoadCSVData
+func (opt defaultCSVOption) Layout(layout *CSVLayout) CSVOpt {
+	return func(c *csvConfig) {
+		c.layout = layout
+	}
+}
+
+func newCSVConfig(opts []CSVOpt) *csvConfig {
+	ret := &csvConfig{
+		comma:   ',',
+		names:   map[string]string{},
+		indexes: map[string]int{},
+	}
+	for _, opt := range opts {
+		opt(ret)
+	}
+	return ret
+}
+
+// 表头单元格对应的字段，无法识别时返回空
+func (c *csvConfig) headerField(cell string) string {
+	cell = strings.TrimSpace(cell)
+	for field, name := range c.names {
+		if strings.EqualFold(cell, name) {
+			return field
+		}
+	}
+	return csvAliases[strings.ToLower(cell)]
+}
+
+func (c *csvConfig) isHeader(row []string) bool {
+	switch c.header {
+	case csvHeaderYes:
+		return true
+	case csvHeaderNo:
+		return false
+	}
+	for _, cell := range row {
+		if c.headerField(cell) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// 导出时的字段顺序：指定了序号的字段按序号排列，其余按默认顺序排在其后
+func (c *csvConfig) fieldOrder() []string {
+	ret := make([]string, len(csvFields))
+	copy(ret, csvFields)
+	sort.SliceStable(ret, func(i, j int) bool {
+		ii, iok := c.indexes[ret[i]]
+		ji, jok := c.indexes[ret[j]]
+		if iok && jok {
+			return ii < ji
+		}
+		return iok && !jok
+	})
+	return ret
+}
+
+func (c *csvConfig) fieldName(field string) string {
+	if name, ok := c.names[field]; ok {
+		return name
+	}
+	return field
+}
+
+// 解析CSV/TSV格式的区域数据，同时返回列布局：有表头时未映射到区域字段的列作为附加列（空值不保存），
+// 无表头时未映射的列以"column序号"（从1开始）为名称
+func LoadCSVData(r io.Reader, opts ...CSVOpt) ([]AreaData, *CSVLayout, error) {
+	conf := newCSVConfig(opts)
+	reader := csv.NewReader(r)
+	reader.Comma = conf.comma
+	reader.FieldsPerRecord = -1
+
+	var ret []AreaData
+	layout := &CSVLayout{
+		Comma: conf.comma,
+		Extra: map[AreaCode]map[string]string{},
+	}
+	lineNo := 0
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, invalidDataError(err)
+		}
+		lineNo++
+		if lineNo == 1 {
+			row[0] = strings.TrimPrefix(row[0], "\ufeff")
+			if conf.isHeader(row) {
+				layout.Header = row
+				layout.Columns = make([]string, len(row))
+				for i, cell := range row {
+					if layout.Columns[i] = conf.headerField(cell); layout.Columns[i] == "" {
+						layout.Columns[i] = cell
+					}
+				}
+				continue
+			}
+		}
+		if layout.Columns == nil {
+			layout.Columns = conf.defaultColumns()
+		}
+		// 无表头时超出的列以"column序号"命名
+		for len(layout.Columns) < len(row) {
+			layout.Columns = append(layout.Columns, fmt.Sprintf("column%d", len(layout.Columns)+1))
+		}
+		ad, ex, err := csvRecord(row, layout.Columns)
+		if err != nil {
+			return nil, nil, &AreaError{
+				Err:   ErrInvalidData,
+				Code:  ad.Code,
+				Cause: fmt.Errorf("line %d: %w", lineNo, err),
+			}
+		}
+		ret = append(ret, ad)
+		if ex != nil {
+			layout.Extra[ad.Code] = ex
+		}
+	}
+	return ret, layout, nil
+}
+
+// 无表头时各列对应的字段，未指定字段的列以"column序号"命名
+func (c *csvConfig) defaultColumns() []string {
+	var ret []string
+	set := func(i int, field string) {
+		for len(ret) <= i {
+			ret = append(ret, fmt.Sprintf("column%d", len(ret)+1))
+		}
+		ret[i] = field
+	}
+	if len(c.indexes) == 0 {
+		for i, f := range csvFields {
+			set(i, f)
+		}
+		return ret
+	}
+	for f, i := range c.indexes {
+		set(i, f)
+	}
+	return ret
+}
+
+func csvRecord(row []string, columns []string) (AreaData, map[string]string, error) {
+	ad := AreaData{}
+	var extra map[string]string
+	for i, cell := range row {
+		column := columns[i]
+		switch column {
+		case CSVCode:
+			ad.Code = AreaCode(strings.TrimSpace(cell))
+		case CSVParentCode:
+			ad.ParentCode = AreaCode(strings.TrimSpace(cell))
+		case CSVLevel:
+			ad.Level = AreaLevel(strings.TrimSpace(cell))
+		case CSVName:
+			ad.Name = cell
+		case CSVLatitude:
+			ad.Latitude = strings.TrimSpace(cell)
+		case CSVLongitude:
+			ad.Longitude = strings.TrimSpace(cell)
+		default:
+			if cell == "" {
+				continue
+			}
+			if extra == nil {
+				extra = map[string]string{}
+			}
+			extra[column] = cell
+		}
+	}
+	if ad.Code == "" {
+		return ad, nil, fmt.Errorf("code missing")
+	}
+	return ad, extra, nil
+}
+
+// CSV数据源最近一次加载的列布局
+type csvLayoutHolder struct {
+	lock   sync.Mutex
+	layout *CSVLayout
+}
+
+func (h *csvLayoutHolder) set(layout *CSVLayout) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.layout = layout
+}
+
+func (h *csvLayoutHolder) get() *CSVLayout {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.layout
+}
+
+// 从CSV/TSV文件加载，见LoadCSVData；列布局及附加列保留在区域服务中，ExportCSV时按原样写出
+func (opt defaultOption) LoadFromCSVFile(path string, opts ...CSVOpt) Opt {
+	return func(s *defaultAreaService) {
+		holder := &csvLayoutHolder{}
+		s.csvLayout = holder
+		s.ds = func() ([]AreaData, error) {
+			f, err := os.Open(path)
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+			data, layout, err := LoadCSVData(f, opts...)
+			if err != nil {
+				return nil, err
+			}
+			holder.set(layout)
+			return data, nil
+		}
+	}
+}
+
+// 将区域数据写出为CSV/TSV，可由LoadCSVData无损读回。
+// 指定了列布局（见DefaultCSVOpt.Layout）时按布局的列顺序、表头及分隔符写出并还原附加列，
+// 布局中没有而数据中有值的区域字段追加在最后；
+// 否则列顺序及表头名称与LoadCSVData使用相同的选项
+func WriteCSV(w io.Writer, data []AreaData, opts ...CSVOpt) error {
+	conf := newCSVConfig(opts)
+	columns, header, comma := conf.exportColumns(data)
+
+	writer := csv.NewWriter(w)
+	writer.Comma = comma
+	if header != nil {
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+	}
+	var extra map[AreaCode]map[string]string
+	if conf.layout != nil {
+		extra = conf.layout.Extra
+	}
+	for _, ad := range data {
+		row := make([]string, len(columns))
+		for i, c := range columns {
+			if v, ok := csvField(ad, c); ok {
+				row[i] = v
+			} else {
+				row[i] = extra[ad.Code][c]
+			}
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// 导出的各列对应的字段或附加列名称、表头（无表头时为nil）及分隔符
+func (c *csvConfig) exportColumns(data []AreaData) ([]string, []string, rune) {
+	if c.layout == nil {
+		if c.header == csvHeaderNo {
+			// 无表头时字段位于其列序号处
+			return c.defaultColumns(), nil, c.comma
+		}
+		columns := c.fieldOrder()
+		header := make([]string, len(columns))
+		for i, col := range columns {
+			header[i] = c.fieldName(col)
+		}
+		return columns, header, c.comma
+	}
+
+	columns := append([]string{}, c.layout.Columns...)
+	var header []string
+	if c.layout.Header != nil {
+		header = append([]string{}, c.layout.Header...)
+	}
+	used := map[string]bool{}
+	for _, col := range columns {
+		used[col] = true
+	}
+	for _, f := range csvFields {
+		if used[f] {
+			continue
+		}
+		for _, ad := range data {
+			if v, _ := csvField(ad, f); v != "" {
+				columns = append(columns, f)
+				if header != nil {
+					header = append(header, f)
+				}
+				break
+			}
+		}
+	}
+	comma := c.layout.Comma
+	if comma == 0 {
+		comma = c.comma
+	}
+	return columns, header, comma
+}
+
+// 将区域服务的原始数据（见AreaService.Data）写出为CSV/TSV，见WriteCSV；
+// 通过DefaultOpt.LoadFromCSVFile加载且未指定列布局时，按加载时的列布局写出
+func ExportCSV(w io.Writer, s AreaService, opts ...CSVOpt) error {
+	data, err := s.Data()
+	if err != nil {
+		return err
+	}
+	if ds, ok := s.(*defaultAreaService); ok && ds.csvLayout != nil {
+		if layout := ds.csvLayout.get(); layout != nil {
+			opts = append([]CSVOpt{DefaultCSVOpt.Layout(layout)}, opts...)
+		}
+	}
+	return WriteCSV(w, data, opts...)
+}
+
+func csvField(ad AreaData, field string) (string, bool) {
+	switch field {
+	case CSVCode:
+		return string(ad.Code), true
+	case CSVParentCode:
+		return string(ad.ParentCode), true
+	case CSVLevel:
+		return string(ad.Level), true
+	case CSVName:
+		return ad.Name, true
+	case CSVLatitude:
+		return ad.Latitude, true
+	case CSVLongitude:
+		return ad.Longitude, true
+	}
+	return "", false
+}
@@ -0,0 +1,187 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description: 比较两份区域数据的差异
+
+package carea
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// 坐标差异阈值，单位度，约0.1米
+const coordinateEpsilon = 1e-6
+
+type DiffType string
+
+const (
+	DiffAdded      = DiffType("added")
+	DiffRemoved    = DiffType("removed")
+	DiffRenamed    = DiffType("renamed")
+	DiffReparented = DiffType("reparented")
+	DiffReleveled  = DiffType("releveled")
+	// 坐标变化，包括坐标新增或缺失
+	DiffCoordinateChanged = DiffType("coordinate_changed")
+)
+
+var diffTypes = []DiffType{DiffAdded, DiffRemoved, DiffRenamed, DiffReparented, DiffReleveled, DiffCoordinateChanged}
+
+type DiffEntry struct {
+	Type DiffType `json:"type"`
+	Code AreaCode `json:"code"`
+	// 旧数据中的区域，新增时为空
+	Old *AreaData `json:"old,omitempty"`
+	// 新数据中的区域，删除时为空
+	New *AreaData `json:"new,omitempty"`
+	// 坐标变化的距离，单位公里，仅新旧坐标均存在时有效
+	Distance float64 `json:"distance,omitempty"`
+}
+
+func (e DiffEntry) String() string {
+	switch e.Type {
+	case DiffAdded:
+		return fmt.Sprintf("[%s] %s %s", e.Type, e.Code, e.New.Name)
+	case DiffRemoved:
+		return fmt.Sprintf("[%s] %s %s", e.Type, e.Code, e.Old.Name)
+	case DiffRenamed:
+		return fmt.Sprintf("[%s] %s %s -> %s", e.Type, e.Code, e.Old.Name, e.New.Name)
+	case DiffReparented:
+		return fmt.Sprintf("[%s] %s %s: parent %s -> %s", e.Type, e.Code, e.New.Name, e.Old.ParentCode, e.New.ParentCode)
+	case DiffReleveled:
+		return fmt.Sprintf("[%s] %s %s: level %s -> %s", e.Type, e.Code, e.New.Name, e.Old.Level, e.New.Level)
+	default:
+		ret := fmt.Sprintf("[%s] %s %s: (%s, %s) -> (%s, %s)", e.Type, e.Code, e.New.Name,
+			e.Old.Latitude, e.Old.Longitude, e.New.Latitude, e.New.Longitude)
+		if e.Distance > 0 {
+			ret += fmt.Sprintf(" %.3fkm", e.Distance)
+		}
+		return ret
+	}
+}
+
+// 区域数据差异报告
+type DiffReport struct {
+	// 旧数据、新数据的记录数
+	OldTotal int `json:"oldTotal"`
+	NewTotal int `json:"newTotal"`
+	// 按Code排序，同一Code按差异类型排序
+	Entries []DiffEntry `json:"entries"`
+}
+
+// 是否没有差异
+func (r *DiffReport) IsEmpty() bool {
+	return len(r.Entries) == 0
+}
+
+// 获得指定类型的差异
+func (r *DiffReport) ByType(t DiffType) []DiffEntry {
+	var ret []DiffEntry
+	for _, e := range r.Entries {
+		if e.Type == t {
+			ret = append(ret, e)
+		}
+	}
+	return ret
+}
+
+func (r *DiffReport) String() string {
+	buf := strings.Builder{}
+	buf.WriteString(fmt.Sprintf("old: %d, new: %d", r.OldTotal, r.NewTotal))
+	for _, t := range diffTypes {
+		buf.WriteString(fmt.Sprintf(", %s: %d", t, len(r.ByType(t))))
+	}
+	for _, e := range r.Entries {
+		buf.WriteString("\n")
+		buf.WriteString(e.String())
+	}
+	return buf.String()
+}
+
+// 比较两个区域服务的数据，报告新增、删除、更名、父区域变化、层级变化及坐标变化的区域
+func Diff(oldService, newService AreaService) (*DiffReport, error) {
+	oldIndex, err := areaIndex(oldService)
+	if err != nil {
+		return nil, err
+	}
+	newIndex, err := areaIndex(newService)
+	if err != nil {
+		return nil, err
+	}
+	ret := &DiffReport{
+		OldTotal: len(oldIndex),
+		NewTotal: len(newIndex),
+	}
+	for code, o := range oldIndex {
+		o := o
+		n, ok := newIndex[code]
+		if !ok {
+			ret.Entries = append(ret.Entries, DiffEntry{Type: DiffRemoved, Code: code, Old: &o})
+			continue
+		}
+		ret.Entries = append(ret.Entries, diffArea(o, n)...)
+	}
+	for code, n := range newIndex {
+		n := n
+		if _, ok := oldIndex[code]; !ok {
+			ret.Entries = append(ret.Entries, DiffEntry{Type: DiffAdded, Code: code, New: &n})
+		}
+	}
+	order := make(map[DiffType]int, len(diffTypes))
+	for i, t := range diffTypes {
+		order[t] = i
+	}
+	sort.Slice(ret.Entries, func(i, j int) bool {
+		if ret.Entries[i].Code != ret.Entries[j].Code {
+			return ret.Entries[i].Code < ret.Entries[j].Code
+		}
+		return order[ret.Entries[i].Type] < order[ret.Entries[j].Type]
+	})
+	return ret, nil
+}
+
+func diffArea(o, n AreaData) []DiffEntry {
+	var ret []DiffEntry
+	entry := func(t DiffType) DiffEntry {
+		o, n := o, n
+		return DiffEntry{Type: t, Code: n.Code, Old: &o, New: &n}
+	}
+	if o.Name != n.Name {
+		ret = append(ret, entry(DiffRenamed))
+	}
+	if o.ParentCode != n.ParentCode {
+		ret = append(ret, entry(DiffReparented))
+	}
+	if o.Level.Int() != n.Level.Int() {
+		ret = append(ret, entry(DiffReleveled))
+	}
+	oLat, oLng, oOk := o.Location()
+	nLat, nLng, nOk := n.Location()
+	switch {
+	case oOk && nOk:
+		if math.Abs(oLat-nLat) > coordinateEpsilon || math.Abs(oLng-nLng) > coordinateEpsilon {
+			e := entry(DiffCoordinateChanged)
+			e.Distance = haversine(oLat, oLng, nLat, nLng)
+			ret = append(ret, e)
+		}
+	case oOk != nOk:
+		ret = append(ret, entry(DiffCoordinateChanged))
+	}
+	return ret
+}
+
+func areaIndex(s AreaService) (map[AreaCode]AreaData, error) {
+	ret := map[AreaCode]AreaData{}
+	for _, lv := range s.AreaLevels() {
+		areas, err := s.AreaByLevel(lv, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range areas {
+			ret[a.Code] = a.AreaData
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,118 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description:
+
+package carea
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// 区域不存在
+	ErrAreaNotFound = errors.New("area not found")
+	// 区域层级超出范围
+	ErrLevelOutOfRange = errors.New("area level out of range")
+	// 区域数据无效
+	ErrInvalidData = errors.New("invalid area data")
+	// 区域名称对应多个区域
+	ErrAmbiguousArea = errors.New("ambiguous area")
+	// 经纬度无效
+	ErrInvalidCoordinate = errors.New("invalid coordinate")
+	// 区域没有边界数据
+	ErrBoundaryNotFound = errors.New("boundary not found")
+	// 区划代码已撤销
+	ErrCodeAbolished = errors.New("area code abolished")
+	// 数据版本不存在
+	ErrVersionNotFound = errors.New("version not found")
+)
+
+// 区域错误，携带出错的区域Code或层级，可通过errors.Is与哨兵错误比较
+type AreaError struct {
+	// 哨兵错误
+	Err error
+	// 相关区域Code
+	Code AreaCode
+	// 相关区域层级
+	Level AreaLevel
+	// 相关区域名称或路径
+	Name string
+	// 名称对应的多个候选区域
+	Candidates []AreaCode
+	// 底层错误
+	Cause error
+}
+
+func (e *AreaError) Error() string {
+	buf := strings.Builder{}
+	buf.WriteString(e.Err.Error())
+	if e.Code != "" {
+		buf.WriteString(fmt.Sprintf(", code: %s", e.Code))
+	}
+	if e.Level != "" {
+		buf.WriteString(fmt.Sprintf(", level: %s", e.Level))
+	}
+	if e.Name != "" {
+		buf.WriteString(fmt.Sprintf(", name: %s", e.Name))
+	}
+	if len(e.Candidates) > 0 {
+		buf.WriteString(fmt.Sprintf(", candidates: %v", e.Candidates))
+	}
+	if e.Cause != nil {
+		buf.WriteString(": ")
+		buf.WriteString(e.Cause.Error())
+	}
+	return buf.String()
+}
+
+func (e *AreaError) Unwrap() error {
+	return e.Err
+}
+
+// 与底层错误比较，使errors.Is(err, os.ErrNotExist)等判断可用
+func (e *AreaError) Is(target error) bool {
+	return e.Cause != nil && errors.Is(e.Cause, target)
+}
+
+func notFoundError(code AreaCode) error {
+	return &AreaError{
+		Err:  ErrAreaNotFound,
+		Code: code,
+	}
+}
+
+func nameNotFoundError(name string) error {
+	return &AreaError{
+		Err:  ErrAreaNotFound,
+		Name: name,
+	}
+}
+
+func ambiguousError(name string, candidates []AreaData) error {
+	codes := make([]AreaCode, len(candidates))
+	for i, c := range candidates {
+		codes[i] = c.Code
+	}
+	return &AreaError{
+		Err:        ErrAmbiguousArea,
+		Name:       name,
+		Candidates: codes,
+	}
+}
+
+func levelError(level AreaLevel) error {
+	return &AreaError{
+		Err:   ErrLevelOutOfRange,
+		Level: level,
+	}
+}
+
+func invalidDataError(cause error) error {
+	return &AreaError{
+		Err:   ErrInvalidData,
+		Cause: cause,
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description: 导出GeoJSON FeatureCollection
+
+package carea
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+const (
+	GeometryPoint        = "Point"
+	GeometryPolygon      = "Polygon"
+	GeometryMultiPolygon = "MultiPolygon"
+)
+
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+type Feature struct {
+	Type string `json:"type"`
+	// 缺失坐标的区域为null
+	Geometry   *Geometry              `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// 坐标顺序为[经度, 纬度]
+type Geometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+type geoJSONConfig struct {
+	keepMissing bool
+	recursion   bool
+	s           AreaService
+}
+
+type GeoJSONOpt func(c *geoJSONConfig)
+
+type defaultGeoJSONOption struct{}
+
+var DefaultGeoJSONOpt defaultGeoJSONOption
+
+// 保留缺失坐标的区域，其geometry为null并带有属性missingCoordinate: true，默认跳过
+func (opt defaultGeoJSONOption) KeepMissing() GeoJSONOpt {
+	return func(c *geoJSONConfig) {
+		c.keepMissing = true
+	}
+}
+
+// 是否导出Subareas中的下级区域，默认导出
+func (opt defaultGeoJSONOption) Recursion(recursion bool) GeoJSONOpt {
+	return func(c *geoJSONConfig) {
+		c.recursion = recursion
+	}
+}
+
+// 从区域服务中获取边界，有边界数据的区域导出为Polygon或MultiPolygon
+func (opt defaultGeoJSONOption) WithBoundary(s AreaService) GeoJSONOpt {
+	return func(c *geoJSONConfig) {
+		c.s = s
+	}
+}
+
+// 将AreaByLevel、SubareaByCode等返回的区域转换为GeoJSON FeatureCollection，
+// 下级区域按先序展开，properties包含code、name、level、parentCode
+func ToGeoJSON(areas []Area, opts ...GeoJSONOpt) (*FeatureCollection, error) {
+	conf := geoJSONConfig{
+		recursion: true,
+	}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	ret := &FeatureCollection{
+		Type:     "FeatureCollection",
+		Features: []Feature{},
+	}
+	var walk func(areas []Area) error
+	walk = func(areas []Area) error {
+		for _, a := range areas {
+			f, ok, err := conf.feature(a.AreaData)
+			if err != nil {
+				return err
+			}
+			if ok {
+				ret.Features = append(ret.Features, f)
+			}
+			if conf.recursion {
+				if err := walk(a.Subareas); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := walk(areas); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (fc *FeatureCollection) String() string {
+	b, _ := json.MarshalIndent(fc, "", "  ")
+	return string(b)
+}
+
+func (c *geoJSONConfig) feature(ad AreaData) (Feature, bool, error) {
+	ret := Feature{
+		Type: "Feature",
+		Properties: map[string]interface{}{
+			"code":       ad.Code,
+			"name":       ad.Name,
+			"level":      ad.Level,
+			"parentCode": ad.ParentCode,
+		},
+	}
+	if c.s != nil {
+		b, err := c.s.Boundary(ad.Code)
+		if err == nil {
+			ret.Geometry = boundaryGeometry(b)
+			return ret, true, nil
+		}
+		if !errors.Is(err, ErrBoundaryNotFound) {
+			return ret, false, err
+		}
+	}
+	lat, lng, ok := ad.Location()
+	if ok {
+		ret.Geometry = &Geometry{
+			Type:        GeometryPoint,
+			Coordinates: []float64{lng, lat},
+		}
+		return ret, true, nil
+	}
+	if !c.keepMissing {
+		return ret, false, nil
+	}
+	ret.Properties["missingCoordinate"] = true
+	return ret, true, nil
+}
+
+func boundaryGeometry(b *Boundary) *Geometry {
+	polygons := make([][][][]float64, len(b.Polygons))
+	for i, p := range b.Polygons {
+		polygons[i] = make([][][]float64, len(p))
+		for j, ring := range p {
+			polygons[i][j] = ringCoordinates(ring)
+		}
+	}
+	if len(polygons) == 1 {
+		return &Geometry{
+			Type:        GeometryPolygon,
+			Coordinates: polygons[0],
+		}
+	}
+	return &Geometry{
+		Type:        GeometryMultiPolygon,
+		Coordinates: polygons,
+	}
+}
+
+// GeoJSON要求环首尾点相同
+func ringCoordinates(r Ring) [][]float64 {
+	ret := make([][]float64, 0, len(r)+1)
+	for _, c := range r {
+		ret = append(ret, []float64{c.Longitude, c.Latitude})
+	}
+	if len(r) > 0 && r[0] != r[len(r)-1] {
+		ret = append(ret, []float64{r[0].Longitude, r[0].Latitude})
+	}
+	return ret
+}
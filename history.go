@@ -0,0 +1,274 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description: 区域数据的历史版本及区划代码变更记录
+
+package carea
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// 内置数据的版本
+const BuildinVersion = "2013"
+
+type ChangeType string
+
+const (
+	// 更名或改设（如县改区），旧代码对应一个新代码
+	ChangeRename = ChangeType("rename")
+	// 合并，旧代码并入一个新代码
+	ChangeMerge = ChangeType("merge")
+	// 拆分，旧代码拆分为多个新代码
+	ChangeSplit = ChangeType("split")
+	// 撤销，旧代码没有对应的新代码
+	ChangeAbolish = ChangeType("abolish")
+)
+
+// 区划代码变更
+type CodeChange struct {
+	// 变更生效的数据版本
+	Version string     `json:"version"`
+	Type    ChangeType `json:"type"`
+	From    AreaCode   `json:"from"`
+	To      []AreaCode `json:"to,omitempty"`
+	// 变更前的区域名称
+	Name string `json:"name,omitempty"`
+	Note string `json:"note,omitempty"`
+}
+
+// 区划代码变更记录
+type ChangeLog []CodeChange
+
+// 内置的区划代码变更记录，覆盖内置数据之后的部分变更
+var buildinChanges = ChangeLog{
+	{Version: "2014", Type: ChangeRename, From: "130182", To: []AreaCode{"130109"}, Name: "藁城市", Note: "撤市设区"},
+	{Version: "2014", Type: ChangeRename, From: "130185", To: []AreaCode{"130110"}, Name: "鹿泉市", Note: "撤市设区"},
+	{Version: "2014", Type: ChangeRename, From: "130124", To: []AreaCode{"130111"}, Name: "栾城县", Note: "撤县设区"},
+	{Version: "2014", Type: ChangeRename, From: "542300", To: []AreaCode{"540200"}, Name: "日喀则地区", Note: "撤地设市"},
+	{Version: "2015", Type: ChangeMerge, From: "320405", To: []AreaCode{"320412"}, Name: "戚墅堰区", Note: "并入武进区"},
+	{Version: "2015", Type: ChangeRename, From: "120221", To: []AreaCode{"120117"}, Name: "宁河县", Note: "撤县设区"},
+	{Version: "2015", Type: ChangeRename, From: "120223", To: []AreaCode{"120118"}, Name: "静海县", Note: "撤县设区"},
+	{Version: "2015", Type: ChangeRename, From: "110228", To: []AreaCode{"110118"}, Name: "密云县", Note: "撤县设区"},
+	{Version: "2015", Type: ChangeRename, From: "110229", To: []AreaCode{"110119"}, Name: "延庆县", Note: "撤县设区"},
+	{Version: "2016", Type: ChangeRename, From: "120225", To: []AreaCode{"120119"}, Name: "蓟县", Note: "撤县设区"},
+}
+
+// 内置的区划代码变更记录
+func BuildinChangeLog() ChangeLog {
+	ret := make(ChangeLog, len(buildinChanges))
+	copy(ret, buildinChanges)
+	return ret
+}
+
+// 解析JSON数组格式的变更记录
+func LoadChangeLog(r io.Reader) (ChangeLog, error) {
+	var ret ChangeLog
+	if err := json.NewDecoder(r).Decode(&ret); err != nil {
+		return nil, invalidDataError(err)
+	}
+	return ret, nil
+}
+
+// 沿变更记录查找代码对应的最新代码，拆分时返回多个代码，没有变更记录时返回代码本身；
+// 变更产生的代码只沿不早于该变更版本的记录继续查找（早于该版本的记录属于代码被重新使用前的区域），
+// 变更记录成环时（如更名后又改回）返回环结束处的代码；全部被撤销时返回ErrCodeAbolished。不检查代码是否存在于区域数据中
+func (l ChangeLog) Resolve(code AreaCode) ([]AreaCode, error) {
+	code = code.Normalize()
+	index := make(map[AreaCode][]CodeChange, len(l))
+	for _, c := range l.sorted() {
+		from := c.From.Normalize()
+		index[from] = append(index[from], c)
+	}
+	type step struct {
+		code  AreaCode
+		since string
+	}
+	var ret []AreaCode
+	found := map[AreaCode]bool{}
+	add := func(c AreaCode) {
+		if !found[c] {
+			found[c] = true
+			ret = append(ret, c)
+		}
+	}
+	// 正在查找及已查找过的步骤，正在查找的步骤再次出现时说明变更记录成环
+	visiting := map[step]bool{}
+	done := map[step]bool{}
+	var resolve func(st step)
+	resolve = func(st step) {
+		if done[st] {
+			return
+		}
+		if visiting[st] {
+			add(st.code)
+			return
+		}
+		visiting[st] = true
+		defer func() {
+			visiting[st] = false
+			done[st] = true
+		}()
+		var changes []CodeChange
+		for _, c := range index[st.code] {
+			if c.Version >= st.since {
+				changes = append(changes, c)
+			}
+		}
+		if len(changes) == 0 {
+			add(st.code)
+			return
+		}
+		for _, change := range changes {
+			for _, to := range change.To {
+				resolve(step{code: to.Normalize(), since: change.Version})
+			}
+		}
+	}
+	// 查找的代码适用其所有变更记录，从最早的变更版本开始
+	start := step{code: code}
+	if changes := index[code]; len(changes) > 0 {
+		start.since = changes[0].Version
+	}
+	resolve(start)
+	if len(ret) == 0 {
+		return nil, &AreaError{
+			Err:  ErrCodeAbolished,
+			Code: code,
+		}
+	}
+	return ret, nil
+}
+
+// 不晚于指定版本（含）的变更记录，version为空时返回全部记录
+func (l ChangeLog) Until(version string) ChangeLog {
+	if version == "" {
+		return l
+	}
+	var ret ChangeLog
+	for _, c := range l {
+		if c.Version <= version {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+// 按版本排序的副本
+func (l ChangeLog) sorted() ChangeLog {
+	ret := make(ChangeLog, len(l))
+	copy(ret, l)
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].Version < ret[j].Version
+	})
+	return ret
+}
+
+// 多个版本的区域数据及其间的变更记录，版本号按字符串排序，建议使用日期格式如"2021"、"2021-10"
+type History struct {
+	versions map[string]DataSource
+	changes  ChangeLog
+}
+
+func NewHistory(changes ...CodeChange) *History {
+	return &History{
+		versions: map[string]DataSource{},
+		changes:  changes,
+	}
+}
+
+// 包含内置数据版本及内置变更记录
+func BuildinHistory() *History {
+	return NewHistory(BuildinChangeLog()...).AddVersion(BuildinVersion, buildinDataSource)
+}
+
+// 添加指定版本的数据源，版本已存在时覆盖
+func (h *History) AddVersion(version string, ds DataSource) *History {
+	h.versions[version] = ds
+	return h
+}
+
+// 添加变更记录
+func (h *History) AddChanges(changes ...CodeChange) *History {
+	h.changes = append(h.changes, changes...)
+	return h
+}
+
+// 从小到大排列的版本号
+func (h *History) Versions() []string {
+	ret := make([]string, 0, len(h.versions))
+	for v := range h.versions {
+		ret = append(ret, v)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// 最新版本号，没有版本时返回空
+func (h *History) Latest() string {
+	vs := h.Versions()
+	if len(vs) == 0 {
+		return ""
+	}
+	return vs[len(vs)-1]
+}
+
+// 指定版本的数据源，version为空时返回最新版本
+func (h *History) DataSource(version string) (DataSource, error) {
+	if version == "" {
+		version = h.Latest()
+	}
+	ds, ok := h.versions[version]
+	if !ok {
+		return nil, versionError(version)
+	}
+	return ds, nil
+}
+
+func (h *History) ChangeLog() ChangeLog {
+	return h.changes
+}
+
+// 使用历史版本中指定版本的数据及其变更记录，version为空时使用最新版本
+func (opt defaultOption) SetHistory(h *History, version string) Opt {
+	return func(s *defaultAreaService) {
+		if version == "" {
+			version = h.Latest()
+		}
+		s.version = version
+		s.changes = h.changes
+		s.ds = func() ([]AreaData, error) {
+			ds, err := h.DataSource(version)
+			if err != nil {
+				return nil, err
+			}
+			return ds()
+		}
+	}
+}
+
+// 设置区划代码变更记录，默认为内置变更记录
+func (opt defaultOption) SetChangeLog(changes ChangeLog) Opt {
+	return func(s *defaultAreaService) {
+		s.changes = changes
+	}
+}
+
+func (s *defaultAreaService) Version() string {
+	return s.version
+}
+
+// 仅使用不晚于数据版本的变更记录，使返回的代码与已加载的数据一致；数据版本为空时使用全部变更记录
+func (s *defaultAreaService) ResolveCode(code AreaCode) ([]AreaCode, error) {
+	return s.changes.Until(s.version).Resolve(code)
+}
+
+func versionError(version string) error {
+	return &AreaError{
+		Err:   ErrVersionNotFound,
+		Cause: fmt.Errorf("version %q", version),
+	}
+}
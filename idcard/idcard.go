@@ -0,0 +1,196 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description: 居民身份证号码（GB 11643）校验与解析
+
+package idcard
+
+import (
+	"errors"
+	"fmt"
+	"github.com/xfali/carea"
+	"strings"
+	"time"
+)
+
+const (
+	// 18位身份证号码长度
+	Length = 18
+	// 15位（一代）身份证号码长度
+	LegacyLength = 15
+
+	birthdayLayout = "20060102"
+)
+
+var (
+	ErrInvalidLength   = errors.New("id number length must be 15 or 18")
+	ErrInvalidFormat   = errors.New("id number contains invalid characters")
+	ErrInvalidChecksum = errors.New("id number checksum mismatch")
+	ErrInvalidBirthday = errors.New("id number birthday invalid")
+	ErrInvalidRegion   = errors.New("id number region invalid")
+)
+
+// ISO 7064 MOD 11-2 加权因子
+var weights = [Length - 1]int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+
+// 余数对应的校验码
+const checkCodes = "10X98765432"
+
+type Gender int
+
+const (
+	Female Gender = iota
+	Male
+)
+
+func (g Gender) String() string {
+	if g == Male {
+		return "male"
+	}
+	return "female"
+}
+
+type Info struct {
+	// 18位身份证号码，15位号码已升级为18位
+	Number string `json:"number"`
+	// 号码中的6位行政区划代码
+	RegionCode carea.AreaCode `json:"regionCode"`
+	// 发证地区域，区划代码已撤销时为仍存在的最近上级区域
+	Region *carea.AreaData `json:"region,omitempty"`
+	// 区划代码是否在当前数据中存在，为false时Region为上级区域
+	RegionExact bool      `json:"regionExact"`
+	Birthday    time.Time `json:"birthday"`
+	Gender      Gender    `json:"gender"`
+}
+
+// 计算18位号码的校验码，number至少包含前17位数字
+func Checksum(number string) (byte, error) {
+	if len(number) < Length-1 {
+		return 0, ErrInvalidLength
+	}
+	sum := 0
+	for i, w := range weights {
+		c := number[i]
+		if c < '0' || c > '9' {
+			return 0, ErrInvalidFormat
+		}
+		sum += int(c-'0') * w
+	}
+	return checkCodes[sum%11], nil
+}
+
+// 将15位号码升级为18位，18位号码校验后原样返回
+func Upgrade(number string) (string, error) {
+	number = strings.ToUpper(strings.TrimSpace(number))
+	switch len(number) {
+	case Length:
+		if err := checkFormat(number); err != nil {
+			return "", err
+		}
+		return number, nil
+	case LegacyLength:
+		if !isDigits(number) {
+			return "", ErrInvalidFormat
+		}
+		ret := number[:6] + "19" + number[6:]
+		c, err := Checksum(ret)
+		if err != nil {
+			return "", err
+		}
+		return ret + string(c), nil
+	}
+	return "", ErrInvalidLength
+}
+
+// 校验号码格式、校验码、出生日期及区划代码格式，不检查区划代码是否存在
+func Validate(number string) error {
+	_, err := parse(number)
+	return err
+}
+
+type Decoder struct {
+	s carea.AreaService
+}
+
+// 创建使用指定区域服务解析发证地的解码器
+func NewDecoder(s carea.AreaService) *Decoder {
+	return &Decoder{s: s}
+}
+
+// 校验并解析号码，发证地区划代码不存在时依次查找其地级、省级区域，均不存在时返回ErrInvalidRegion
+func (d *Decoder) Decode(number string) (*Info, error) {
+	ret, err := parse(number)
+	if err != nil {
+		return nil, err
+	}
+	a, exact, err := carea.AreaOrAncestor(d.s, ret.RegionCode)
+	if err != nil {
+		if errors.Is(err, carea.ErrAreaNotFound) {
+			return nil, fmt.Errorf("%w: region %s not found", ErrInvalidRegion, ret.RegionCode)
+		}
+		return nil, err
+	}
+	ret.Region = &a
+	ret.RegionExact = exact
+	return ret, nil
+}
+
+func parse(number string) (*Info, error) {
+	number = strings.ToUpper(strings.TrimSpace(number))
+	if len(number) == LegacyLength {
+		upgraded, err := Upgrade(number)
+		if err != nil {
+			return nil, err
+		}
+		number = upgraded
+	} else if err := checkFormat(number); err != nil {
+		return nil, err
+	}
+
+	c, err := Checksum(number)
+	if err != nil {
+		return nil, err
+	}
+	if c != number[Length-1] {
+		return nil, ErrInvalidChecksum
+	}
+
+	region := carea.AreaCode(number[:6])
+	if !region.IsValidFormat() {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRegion, region)
+	}
+	birthday, err := time.ParseInLocation(birthdayLayout, number[6:14], time.Local)
+	if err != nil || birthday.After(time.Now()) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidBirthday, number[6:14])
+	}
+	gender := Female
+	if (number[16]-'0')%2 == 1 {
+		gender = Male
+	}
+	return &Info{
+		Number:     number,
+		RegionCode: region,
+		Birthday:   birthday,
+		Gender:     gender,
+	}, nil
+}
+
+func checkFormat(number string) error {
+	if len(number) != Length {
+		return ErrInvalidLength
+	}
+	last := number[Length-1]
+	if !isDigits(number[:Length-1]) || !(last == 'X' || (last >= '0' && last <= '9')) {
+		return ErrInvalidFormat
+	}
+	return nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
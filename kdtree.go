@@ -0,0 +1,151 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description: 基于单位球面三维坐标的k-d树，弦长与球面距离单调一致
+
+package carea
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+type kdPoint struct {
+	pos  [3]float64
+	area AreaData
+}
+
+type kdNode struct {
+	point       kdPoint
+	axis        int
+	left, right *kdNode
+}
+
+type kdTree struct {
+	root   *kdNode
+	points []kdPoint
+}
+
+// 经纬度转换为单位球面上的三维坐标
+func toCartesian(lat, lng float64) [3]float64 {
+	rad := math.Pi / 180
+	cosLat := math.Cos(lat * rad)
+	return [3]float64{
+		cosLat * math.Cos(lng*rad),
+		cosLat * math.Sin(lng*rad),
+		math.Sin(lat * rad),
+	}
+}
+
+func newKdTree(points []kdPoint) *kdTree {
+	return &kdTree{
+		root:   buildKdNode(points, 0),
+		points: points,
+	}
+}
+
+func buildKdNode(points []kdPoint, depth int) *kdNode {
+	if len(points) == 0 {
+		return nil
+	}
+	axis := depth % 3
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].pos[axis] < points[j].pos[axis]
+	})
+	mid := len(points) / 2
+	return &kdNode{
+		point: points[mid],
+		axis:  axis,
+		left:  buildKdNode(points[:mid], depth+1),
+		right: buildKdNode(points[mid+1:], depth+1),
+	}
+}
+
+type kdResult struct {
+	point kdPoint
+	// 弦长的平方
+	dist2 float64
+}
+
+// 按距离从大到小排列的堆，堆顶为当前第k近的点
+type kdHeap []kdResult
+
+func (h kdHeap) Len() int            { return len(h) }
+func (h kdHeap) Less(i, j int) bool  { return h[i].dist2 > h[j].dist2 }
+func (h kdHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *kdHeap) Push(x interface{}) { *h = append(*h, x.(kdResult)) }
+func (h *kdHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	ret := old[n-1]
+	*h = old[:n-1]
+	return ret
+}
+
+// 距离target最近的k个点，按距离从近到远排列
+func (t *kdTree) nearest(target [3]float64, k int) []kdResult {
+	if k <= 0 || t.root == nil {
+		return nil
+	}
+	h := make(kdHeap, 0, k)
+	var search func(n *kdNode)
+	search = func(n *kdNode) {
+		if n == nil {
+			return
+		}
+		d2 := dist2(n.point.pos, target)
+		if len(h) < k {
+			heap.Push(&h, kdResult{point: n.point, dist2: d2})
+		} else if d2 < h[0].dist2 {
+			h[0] = kdResult{point: n.point, dist2: d2}
+			heap.Fix(&h, 0)
+		}
+		diff := target[n.axis] - n.point.pos[n.axis]
+		near, far := n.left, n.right
+		if diff > 0 {
+			near, far = far, near
+		}
+		search(near)
+		if len(h) < k || diff*diff < h[0].dist2 {
+			search(far)
+		}
+	}
+	search(t.root)
+
+	ret := make([]kdResult, len(h))
+	for i := len(h) - 1; i >= 0; i-- {
+		ret[i] = heap.Pop(&h).(kdResult)
+	}
+	return ret
+}
+
+// 弦长平方不超过maxDist2的所有点，未排序
+func (t *kdTree) within(target [3]float64, maxDist2 float64) []kdResult {
+	var ret []kdResult
+	var search func(n *kdNode)
+	search = func(n *kdNode) {
+		if n == nil {
+			return
+		}
+		if d2 := dist2(n.point.pos, target); d2 <= maxDist2 {
+			ret = append(ret, kdResult{point: n.point, dist2: d2})
+		}
+		diff := target[n.axis] - n.point.pos[n.axis]
+		near, far := n.left, n.right
+		if diff > 0 {
+			near, far = far, near
+		}
+		search(near)
+		if diff*diff <= maxDist2 {
+			search(far)
+		}
+	}
+	search(t.root)
+	return ret
+}
+
+func dist2(a, b [3]float64) float64 {
+	dx, dy, dz := a[0]-b[0], a[1]-b[1], a[2]-b[2]
+	return dx*dx + dy*dy + dz*dz
+}
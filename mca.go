@@ -0,0 +1,91 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description: 民政部行政区划代码表加载
+
+package carea
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	// 直辖市下辖区、县的地级代码段
+	municipalityDistrictSegment = "01"
+	municipalityCountySegment   = "02"
+	// 省、自治区直辖县级行政区划的地级代码段
+	directCountySegment = "90"
+)
+
+// 解析民政部行政区划代码表，每行格式为"行政区划代码 名称"，字段分隔规则同LoadNBSData，
+// ParentCode与Level根据GB/T 2260代码结构推导。
+// 代码表不含直辖市的市辖区、市辖县及省直辖县级行政区划等统计用占位记录，
+// 这些记录按内置数据的命名自动补充，使层级结构与内置数据一致
+func LoadMCAData(r io.Reader) ([]AreaData, error) {
+	ret, err := parseCodeTable(r)
+	if err != nil {
+		return nil, err
+	}
+	ret = append(ret, placeholderAreas(ret)...)
+	fillHierarchy(ret)
+	return ret, nil
+}
+
+// 从民政部行政区划代码表文件加载，见LoadMCAData
+func (opt defaultOption) LoadFromMCAFile(path string) Opt {
+	return func(s *defaultAreaService) {
+		s.ds = func() ([]AreaData, error) {
+			f, err := os.Open(path)
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+			return LoadMCAData(f)
+		}
+	}
+}
+
+// 缺失的地级占位记录：直辖市的市辖区、市辖县，省（自治区）直辖县级行政区划
+func placeholderAreas(data []AreaData) []AreaData {
+	index := make(map[AreaCode]AreaData, len(data))
+	for _, ad := range data {
+		index[ad.Code] = ad
+	}
+	var ret []AreaData
+	for _, ad := range data {
+		if ad.Code.InferredLevel() != CountyLevel {
+			continue
+		}
+		prefecture := ad.Code.PrefectureCode()
+		if _, ok := index[prefecture]; ok {
+			continue
+		}
+		province, ok := index[ad.Code.ProvinceCode()]
+		if !ok {
+			continue
+		}
+		name := ""
+		segment := string(prefecture[provinceSegment:prefectureSegment])
+		switch {
+		case ad.Code.IsMunicipalityDistrict() && segment == municipalityDistrictSegment:
+			name = "市辖区"
+		case ad.Code.IsMunicipalityDistrict() && segment == municipalityCountySegment:
+			name = "市辖县"
+		case segment == directCountySegment && strings.HasSuffix(province.Name, "自治区"):
+			name = "自治区直辖县级行政区划"
+		case segment == directCountySegment:
+			name = "省直辖县级行政区划"
+		default:
+			continue
+		}
+		p := AreaData{
+			Code: prefecture,
+			Name: name,
+		}
+		index[prefecture] = p
+		ret = append(ret, p)
+	}
+	return ret
+}
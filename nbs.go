@@ -0,0 +1,102 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description: 国家统计局统计用区划代码表加载
+
+package carea
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// 解析国家统计局统计用区划代码表，每行格式为"统计用区划代码 [城乡分类代码] 名称"，
+// 字段以空白、制表符或逗号分隔，代码可为12位或6、9位，首列非数字的行（如表头）及#开头的行被忽略。
+// ParentCode与Level根据代码结构推导。
+func LoadNBSData(r io.Reader) ([]AreaData, error) {
+	ret, err := parseCodeTable(r)
+	if err != nil {
+		return nil, err
+	}
+	fillHierarchy(ret)
+	return ret, nil
+}
+
+// 解析"代码 [其他字段] 名称"格式的代码表，名称取每行最后一个字段，不填充ParentCode与Level
+func parseCodeTable(r io.Reader) ([]AreaData, error) {
+	var ret []AreaData
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.FieldsFunc(line, func(r rune) bool {
+			return r == ',' || r == '\t' || r == ' ' || r == '　'
+		})
+		if len(fields) == 0 || !isDigits(fields[0]) {
+			continue
+		}
+		if len(fields) < 2 {
+			return nil, invalidDataError(fmt.Errorf("line %d: name missing", lineNo))
+		}
+		code := AreaCode(fields[0])
+		if !code.IsValidFormat() {
+			return nil, &AreaError{
+				Err:   ErrInvalidData,
+				Code:  code,
+				Cause: fmt.Errorf("line %d: invalid code", lineNo),
+			}
+		}
+		// 村级记录带有3位城乡分类代码
+		name := fields[len(fields)-1]
+		ret = append(ret, AreaData{
+			Code: code.Normalize(),
+			Name: name,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// 从国家统计局统计用区划代码表文件加载，见LoadNBSData
+func (opt defaultOption) LoadFromNBSFile(path string) Opt {
+	return func(s *defaultAreaService) {
+		s.ds = func() ([]AreaData, error) {
+			f, err := os.Open(path)
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+			return LoadNBSData(f)
+		}
+	}
+}
+
+// 根据代码结构填充ParentCode和Level，
+// 推导的父区域不在数据中时（如直辖市区县缺少市辖区记录）逐级向上查找已存在的祖先区域
+func fillHierarchy(data []AreaData) {
+	codes := make(map[AreaCode]bool, len(data))
+	for _, ad := range data {
+		codes[ad.Code] = true
+	}
+	for i := range data {
+		code := data[i].Code
+		parent := code.InferredParentCode()
+		for parent != RootParentCode && parent != "" && !codes[parent] {
+			parent = parent.InferredParentCode()
+		}
+		if parent == "" {
+			parent = RootParentCode
+		}
+		data[i].ParentCode = parent
+		data[i].Level = code.InferredLevel()
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description: 按层级路径查找区域
+
+package carea
+
+import "strings"
+
+const DefaultPathSeparator = "/"
+
+// 占位区域名称，这些区域没有实际的行政区划名称，仅用于组织下级区域
+var placeholderNames = map[string]bool{
+	"市辖区":         true,
+	"市辖县":         true,
+	"县":           true,
+	"省直辖县级行政区划":   true,
+	"自治区直辖县级行政区划": true,
+}
+
+// 是否为占位区域，如北京市下的市辖区、湖北省下的省直辖县级行政区划
+func (a AreaData) IsPlaceholder() bool {
+	return placeholderNames[a.Name]
+}
+
+type pathConfig struct {
+	separator       string
+	skipPlaceholder bool
+}
+
+type PathOpt func(c *pathConfig)
+
+type defaultPathOption struct{}
+
+var DefaultPathOpt defaultPathOption
+
+// 路径分隔符，默认为DefaultPathSeparator
+func (opt defaultPathOption) Separator(sep string) PathOpt {
+	return func(c *pathConfig) {
+		c.separator = sep
+	}
+}
+
+// 路径中可省略占位区域，如"北京市/朝阳区"可匹配"北京市/市辖区/朝阳区"
+func (opt defaultPathOption) SkipPlaceholder() PathOpt {
+	return func(c *pathConfig) {
+		c.skipPlaceholder = true
+	}
+}
+
+func (s *defaultAreaService) AreaByPath(path string, withSub bool, opts ...PathOpt) (Area, error) {
+	conf := pathConfig{
+		separator: DefaultPathSeparator,
+	}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	var segments []string
+	for _, seg := range strings.Split(path, conf.separator) {
+		if seg = strings.TrimSpace(seg); seg != "" {
+			segments = append(segments, seg)
+		}
+	}
+	if len(segments) == 0 {
+		return Area{}, nameNotFoundError(path)
+	}
+
+	// 首段可为任意层级的区域，之后每段在上一段区域的下级区域中查找
+	var all []AreaData
+	for _, lv := range s.areas {
+		all = append(all, lv...)
+	}
+	candidates := matchAreaName(all, segments[0])
+	for _, seg := range segments[1:] {
+		var next []AreaData
+		for _, c := range candidates {
+			next = append(next, matchAreaName(s.pathChildren(c.Code, conf.skipPlaceholder), seg)...)
+		}
+		candidates = next
+	}
+	return s.uniqueArea(path, candidates, withSub)
+}
+
+func (s *defaultAreaService) AreaByNameUnder(ancestor AreaCode, name string, withSub bool) (Area, error) {
+	ancestor = ancestor.Normalize()
+	if _, ok := s.codeIndex[ancestor]; !ok {
+		return Area{}, notFoundError(ancestor)
+	}
+	return s.uniqueArea(name, matchAreaName(s.descendants(ancestor), name), withSub)
+}
+
+func (s *defaultAreaService) uniqueArea(name string, candidates []AreaData, withSub bool) (Area, error) {
+	switch len(candidates) {
+	case 0:
+		return Area{}, nameNotFoundError(name)
+	case 1:
+		ret := Area{
+			AreaData: candidates[0],
+		}
+		if withSub {
+			_ = s.getChildren(&ret, true)
+		}
+		return ret, nil
+	default:
+		return Area{}, ambiguousError(name, candidates)
+	}
+}
+
+// 路径中的下级区域，跳过占位区域时包含占位区域的下级区域
+func (s *defaultAreaService) pathChildren(code AreaCode, skipPlaceholder bool) []AreaData {
+	var ret []AreaData
+	for _, c := range s.childIndex[code] {
+		ret = append(ret, c)
+		if skipPlaceholder && c.IsPlaceholder() {
+			ret = append(ret, s.pathChildren(c.Code, skipPlaceholder)...)
+		}
+	}
+	return ret
+}
+
+// 所有下级区域
+func (s *defaultAreaService) descendants(code AreaCode) []AreaData {
+	var ret []AreaData
+	for _, c := range s.childIndex[code] {
+		ret = append(ret, c)
+		ret = append(ret, s.descendants(c.Code)...)
+	}
+	return ret
+}
+
+// 按名称匹配区域，优先完全匹配，没有完全匹配时忽略行政区划后缀匹配
+func matchAreaName(areas []AreaData, name string) []AreaData {
+	var exact, short []AreaData
+	shortName := ShortName(name)
+	for _, a := range areas {
+		if a.Name == name {
+			exact = append(exact, a)
+		} else if ShortName(a.Name) == shortName {
+			short = append(short, a)
+		}
+	}
+	if len(exact) > 0 {
+		return exact
+	}
+	return short
+}
+
+type nameConfig struct {
+	separator           string
+	collapsePlaceholder bool
+	dropDuplicate       bool
+}
+
+type NameOpt func(c *nameConfig)
+
+type defaultNameOption struct{}
+
+var DefaultNameOpt defaultNameOption
+
+// 各级名称之间的分隔符，默认不分隔
+func (opt defaultNameOption) Separator(sep string) NameOpt {
+	return func(c *nameConfig) {
+		c.separator = sep
+	}
+}
+
+// 省略占位区域（市辖区、市辖县、省直辖县级行政区划等），区域本身为占位区域时保留
+func (opt defaultNameOption) CollapsePlaceholder() NameOpt {
+	return func(c *nameConfig) {
+		c.collapsePlaceholder = true
+	}
+}
+
+// 省略与上级区域同名的区域，如直辖市下与直辖市同名的地级区域
+func (opt defaultNameOption) DropDuplicate() NameOpt {
+	return func(c *nameConfig) {
+		c.dropDuplicate = true
+	}
+}
+
+func (s *defaultAreaService) Path(code AreaCode) ([]AreaData, error) {
+	ad, ok := s.codeIndex[code.Normalize()]
+	if !ok {
+		return nil, notFoundError(code)
+	}
+	ret := []AreaData{ad}
+	for p, ok := s.parentArea(ad); ok; p, ok = s.parentArea(p) {
+		ret = append(ret, p)
+	}
+	for i, j := 0, len(ret)-1; i < j; i, j = i+1, j-1 {
+		ret[i], ret[j] = ret[j], ret[i]
+	}
+	return ret, nil
+}
+
+func (s *defaultAreaService) FullName(code AreaCode, opts ...NameOpt) (string, error) {
+	conf := nameConfig{}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	path, err := s.Path(code)
+	if err != nil {
+		return "", err
+	}
+	names := make([]string, 0, len(path))
+	last := len(path) - 1
+	for i, ad := range path {
+		if i < last && conf.collapsePlaceholder && ad.IsPlaceholder() {
+			continue
+		}
+		if conf.dropDuplicate && len(names) > 0 && names[len(names)-1] == ad.Name {
+			continue
+		}
+		names = append(names, ad.Name)
+	}
+	return strings.Join(names, conf.separator), nil
+}
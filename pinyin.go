@@ -0,0 +1,150 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description: 区域名称拼音
+
+package carea
+
+import (
+	"github.com/xfali/carea/static"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 多音字地名的拼音，优先于单字拼音按最长匹配使用
+var pinyinPhrases = map[string][]string{
+	"重庆":  {"chong", "qing"},
+	"蚌埠":  {"beng", "bu"},
+	"蚌山":  {"beng", "shan"},
+	"称多":  {"chen", "duo"},
+	"六安":  {"lu", "an"},
+	"六合":  {"lu", "he"},
+	"番禺":  {"pan", "yu"},
+	"乐亭":  {"lao", "ting"},
+	"乐清":  {"yue", "qing"},
+	"荥经":  {"ying", "jing"},
+	"洪洞":  {"hong", "tong"},
+	"单县":  {"shan", "xian"},
+	"泌阳":  {"bi", "yang"},
+	"浚县":  {"xun", "xian"},
+	"铅山":  {"yan", "shan"},
+	"犍为":  {"qian", "wei"},
+	"筠连":  {"jun", "lian"},
+	"尉犁":  {"yu", "li"},
+	"蔚县":  {"yu", "xian"},
+	"涡阳":  {"guo", "yang"},
+	"牟平":  {"mu", "ping"},
+	"中牟":  {"zhong", "mu"},
+	"费县":  {"bi", "xian"},
+	"枞阳":  {"zong", "yang"},
+	"珲春":  {"hun", "chun"},
+	"黄陂":  {"huang", "pi"},
+	"宝坻":  {"bao", "di"},
+	"泊头":  {"bo", "tou"},
+	"长子":  {"zhang", "zi"},
+	"黄埔":  {"huang", "pu"},
+	"厦门":  {"xia", "men"},
+	"东阿":  {"dong", "e"},
+	"繁峙":  {"fan", "shi"},
+	"吴堡":  {"wu", "bu"},
+	"崆峒":  {"kong", "tong"},
+	"红寺堡": {"hong", "si", "bu"},
+}
+
+const maxPhraseLength = 3
+
+// 名称的拼音音节，不带声调，ü写作v；字母、数字原样保留为小写，其他无拼音的字符被忽略
+func ToPinyin(name string) []string {
+	var ret []string
+	for name != "" {
+		if syllables, n := matchPhrase(name); n > 0 {
+			ret = append(ret, syllables...)
+			name = name[n:]
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(name)
+		name = name[size:]
+		if py, ok := static.Pinyin[r]; ok {
+			ret = append(ret, py)
+		} else if r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			ret = append(ret, string(unicode.ToLower(r)))
+		}
+	}
+	return ret
+}
+
+func matchPhrase(name string) ([]string, int) {
+	end := 0
+	for i := 0; i < maxPhraseLength && end < len(name); i++ {
+		_, size := utf8.DecodeRuneInString(name[end:])
+		end += size
+	}
+	for end > 0 {
+		if syllables, ok := pinyinPhrases[name[:end]]; ok {
+			return syllables, end
+		}
+		_, size := utf8.DecodeLastRuneInString(name[:end])
+		end -= size
+	}
+	return nil, 0
+}
+
+// 名称的全拼，如成都市 -> chengdushi
+func FullPinyin(name string) string {
+	return strings.Join(ToPinyin(name), "")
+}
+
+// 名称的拼音首字母，如成都市 -> cds
+func PinyinInitials(name string) string {
+	buf := strings.Builder{}
+	for _, py := range ToPinyin(name) {
+		buf.WriteByte(py[0])
+	}
+	return buf.String()
+}
+
+// 填充区域的拼音字段
+func fillPinyin(data []AreaData) {
+	for i := range data {
+		data[i].Pinyin = FullPinyin(data[i].Name)
+		data[i].PinyinInitials = PinyinInitials(data[i].Name)
+	}
+}
+
+// 规范化拼音关键字，去掉空格、隔音符号并转为小写，非拼音关键字返回空
+func normalizePinyinKeyword(keyword string) string {
+	buf := strings.Builder{}
+	for _, r := range strings.ToLower(keyword) {
+		switch {
+		case r == ' ' || r == '\'':
+		case r == 'ü':
+			buf.WriteByte('v')
+		case r >= 'a' && r <= 'z':
+			buf.WriteRune(r)
+		default:
+			return ""
+		}
+	}
+	return buf.String()
+}
+
+// 拼音匹配得分，依次尝试全拼、首字母的完全匹配及前缀匹配，不匹配时返回0
+func matchPinyin(ad AreaData, keyword string) float64 {
+	full, initials := ad.Pinyin, ad.PinyinInitials
+	if full == "" {
+		full, initials = FullPinyin(ad.Name), PinyinInitials(ad.Name)
+	}
+	short := ShortName(ad.Name)
+	switch {
+	case keyword == full || keyword == FullPinyin(short):
+		return scorePinyin
+	case keyword == initials || keyword == PinyinInitials(short):
+		return scorePinyinInitials
+	case strings.HasPrefix(full, keyword):
+		return scorePinyinPrefix + scoreCoverage*float64(len(keyword))/float64(len(full))
+	case strings.HasPrefix(initials, keyword):
+		return scorePinyinInitialsPrefix + scoreCoverage*float64(len(keyword))/float64(len(initials))
+	}
+	return 0
+}
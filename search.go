@@ -0,0 +1,253 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description: 区域名称搜索
+
+package carea
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+type MatchMode int
+
+const (
+	// 名称完全匹配
+	MatchExact MatchMode = 1 << iota
+	// 名称前缀匹配
+	MatchPrefix
+	// 名称包含关键字
+	MatchContains
+	// 忽略行政区划后缀（省、市、自治区等）及民族名称后匹配
+	MatchSuffixInsensitive
+	// 拼音全拼或首字母匹配，如chengdu、cd
+	MatchPinyin
+
+	// 所有匹配方式
+	MatchAll = MatchExact | MatchPrefix | MatchContains | MatchSuffixInsensitive | MatchPinyin
+)
+
+const (
+	scoreExact             = 1.0
+	scoreSuffixInsensitive = 0.9
+	scorePrefix            = 0.5
+	scoreContains          = 0.2
+	// 拼音匹配得分低于同等程度的汉字匹配
+	scorePinyin               = 0.85
+	scorePinyinInitials       = 0.7
+	scorePinyinPrefix         = 0.4
+	scorePinyinInitialsPrefix = 0.3
+	// 关键字占名称比例的加分权重
+	scoreCoverage = 0.3
+)
+
+// 行政区划后缀，按长度从长到短匹配
+var areaSuffixes = []string{
+	"特别行政区",
+	"自治区", "自治州", "自治县", "自治旗",
+	"地区", "林区",
+	"省", "市", "盟", "县", "区", "旗",
+}
+
+// 民族名称，用于去掉民族自治地方名称中的民族部分
+var ethnicNames = []string{
+	"乌孜别克", "柯尔克孜", "维吾尔", "哈萨克", "达斡尔", "鄂伦春", "鄂温克", "俄罗斯", "塔吉克", "塔塔尔",
+	"蒙古", "布依", "朝鲜", "土家", "哈尼", "傈僳", "拉祜", "东乡", "纳西", "景颇", "仫佬", "布朗", "撒拉",
+	"毛南", "仡佬", "锡伯", "阿昌", "普米", "德昂", "保安", "裕固", "独龙", "赫哲", "门巴", "珞巴", "基诺", "高山",
+	"汉", "回", "藏", "苗", "彝", "壮", "满", "侗", "瑶", "白", "傣", "黎", "佤", "畲", "水", "土", "羌", "怒", "京", "各",
+}
+
+type SearchResult struct {
+	AreaData
+	// 匹配得分，取值(0, 1]，越大越匹配
+	Score float64 `json:"score"`
+	// 得分最高的匹配方式
+	Mode MatchMode `json:"mode"`
+}
+
+type searchConfig struct {
+	mode     MatchMode
+	levels   map[AreaLevel]bool
+	ancestor AreaCode
+	limit    int
+}
+
+type SearchOpt func(c *searchConfig)
+
+type defaultSearchOption struct{}
+
+var DefaultSearchOpt defaultSearchOption
+
+// 设置匹配方式，可组合，如MatchExact|MatchPrefix，默认为MatchAll
+func (opt defaultSearchOption) Mode(mode MatchMode) SearchOpt {
+	return func(c *searchConfig) {
+		c.mode = mode
+	}
+}
+
+// 只返回指定层级的区域
+func (opt defaultSearchOption) Level(levels ...AreaLevel) SearchOpt {
+	return func(c *searchConfig) {
+		if c.levels == nil {
+			c.levels = map[AreaLevel]bool{}
+		}
+		for _, lv := range levels {
+			c.levels[Int2AreaLevel(lv.Int())] = true
+		}
+	}
+}
+
+// 只返回指定区域的下级区域（不含该区域本身）
+func (opt defaultSearchOption) Under(ancestor AreaCode) SearchOpt {
+	return func(c *searchConfig) {
+		c.ancestor = ancestor.Normalize()
+	}
+}
+
+// 最多返回的结果数，小于等于0时不限制
+func (opt defaultSearchOption) Limit(n int) SearchOpt {
+	return func(c *searchConfig) {
+		c.limit = n
+	}
+}
+
+func (s *defaultAreaService) Search(keyword string, opts ...SearchOpt) ([]SearchResult, error) {
+	conf := searchConfig{
+		mode: MatchAll,
+	}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" {
+		return nil, nil
+	}
+	if conf.ancestor != "" {
+		if _, ok := s.codeIndex[conf.ancestor]; !ok {
+			return nil, notFoundError(conf.ancestor)
+		}
+	}
+
+	pinyin := ""
+	if conf.mode&MatchPinyin != 0 {
+		pinyin = normalizePinyinKeyword(keyword)
+	}
+
+	var ret []SearchResult
+	for _, lv := range s.areas {
+		for _, ad := range lv {
+			if conf.levels != nil && !conf.levels[Int2AreaLevel(ad.Level.Int())] {
+				continue
+			}
+			score, mode := matchName(ad.Name, keyword, conf.mode)
+			if score == 0 && pinyin != "" {
+				score, mode = matchPinyin(ad, pinyin), MatchPinyin
+			}
+			if score == 0 {
+				continue
+			}
+			if conf.ancestor != "" && !s.isDescendant(ad, conf.ancestor) {
+				continue
+			}
+			ret = append(ret, SearchResult{
+				AreaData: ad,
+				Score:    score,
+				Mode:     mode,
+			})
+		}
+	}
+	sortSearchResults(ret)
+	if conf.limit > 0 && len(ret) > conf.limit {
+		ret = ret[:conf.limit]
+	}
+	return ret, nil
+}
+
+// 区域是否为ancestor的下级区域
+func (s *defaultAreaService) isDescendant(ad AreaData, ancestor AreaCode) bool {
+	for p, ok := s.parentArea(ad); ok; p, ok = s.parentArea(p) {
+		if p.Code == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// 按得分从高到低排序，得分相同时层级高者及Code小者优先
+func sortSearchResults(ret []SearchResult) {
+	sort.SliceStable(ret, func(i, j int) bool {
+		if ret[i].Score != ret[j].Score {
+			return ret[i].Score > ret[j].Score
+		}
+		if ret[i].Level.Int() != ret[j].Level.Int() {
+			return ret[i].Level.Int() < ret[j].Level.Int()
+		}
+		return ret[i].Code < ret[j].Code
+	})
+}
+
+// 返回最高得分及对应匹配方式，不匹配时得分为0
+func matchName(name, keyword string, mode MatchMode) (float64, MatchMode) {
+	if mode&MatchExact != 0 && name == keyword {
+		return scoreExact, MatchExact
+	}
+	if mode&MatchSuffixInsensitive != 0 && ShortName(name) == ShortName(keyword) {
+		return scoreSuffixInsensitive, MatchSuffixInsensitive
+	}
+	coverage := float64(utf8.RuneCountInString(keyword)) / float64(utf8.RuneCountInString(name))
+	if mode&MatchPrefix != 0 && strings.HasPrefix(name, keyword) {
+		return scorePrefix + scoreCoverage*coverage, MatchPrefix
+	}
+	if mode&MatchContains != 0 && strings.Contains(name, keyword) {
+		return scoreContains + scoreCoverage*coverage, MatchContains
+	}
+	return 0, 0
+}
+
+// 去掉行政区划后缀及民族自治地方的民族名称，如：
+// 四川省 -> 四川，广西壮族自治区 -> 广西，凉山彝族自治州 -> 凉山，伊犁哈萨克自治州 -> 伊犁。
+// 去掉后缀后为空时返回原名称
+func ShortName(name string) string {
+	ret := name
+	autonomous := false
+	for _, suffix := range areaSuffixes {
+		if strings.HasSuffix(ret, suffix) && len(ret) > len(suffix) {
+			ret = strings.TrimSuffix(ret, suffix)
+			autonomous = strings.HasPrefix(suffix, "自治")
+			break
+		}
+	}
+	for {
+		trimmed := trimEthnicName(ret, autonomous)
+		if trimmed == ret {
+			return ret
+		}
+		ret = trimmed
+	}
+}
+
+// 去掉末尾的一个民族名称，"族"字可省略的仅限民族自治地方，且剩余部分至少保留2个字
+func trimEthnicName(name string, autonomous bool) string {
+	s := name
+	withZu := strings.HasSuffix(s, "族")
+	if withZu {
+		s = strings.TrimSuffix(s, "族")
+	} else if !autonomous {
+		return name
+	}
+	for _, e := range ethnicNames {
+		if !withZu && utf8.RuneCountInString(e) < 2 {
+			continue
+		}
+		if strings.HasSuffix(s, e) {
+			rest := strings.TrimSuffix(s, e)
+			if utf8.RuneCountInString(rest) < 2 {
+				return name
+			}
+			return rest
+		}
+	}
+	return name
+}
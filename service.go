@@ -6,15 +6,19 @@
 package carea
 
 import (
-	"encoding/json"
 	"fmt"
 	"github.com/xfali/carea/static"
+	"io"
+	"io/fs"
 	"io/ioutil"
+	"sync"
 )
 
 const (
 	TopLevel    = AreaLevel("1")
 	TopLevelInt = 1
+	// 支持的最大层级，即村级VillageLevel
+	MaxLevelInt = 5
 )
 
 type AreaService interface {
@@ -41,6 +45,83 @@ type AreaService interface {
 	// withSub： 是否遍历子区域
 	AreaByName(name string, withSub bool) ([]Area, error)
 
+	// 按层级路径获得区域信息，如"四川省/成都市/武侯区"，各段可省略行政区划后缀，匹配多个区域时返回ErrAmbiguousArea
+	// path：区域路径，首段可为任意层级的区域
+	// withSub： 是否遍历子区域
+	// opts：路径分隔符、是否跳过占位区域，见DefaultPathOpt
+	AreaByPath(path string, withSub bool, opts ...PathOpt) (Area, error)
+
+	// 获得指定区域下指定名称的区域信息，匹配多个区域时返回ErrAmbiguousArea
+	// ancestor：上级区域Code
+	// name：区域名称
+	// withSub： 是否遍历子区域
+	AreaByNameUnder(ancestor AreaCode, name string, withSub bool) (Area, error)
+
+	// 获得从顶级区域到指定区域的路径
+	// code：指定区域Code
+	Path(code AreaCode) ([]AreaData, error)
+
+	// 获得指定区域的完整名称，如"四川省成都市武侯区"
+	// code：指定区域Code
+	// opts：分隔符、省略占位区域等，见DefaultNameOpt
+	FullName(code AreaCode, opts ...NameOpt) (string, error)
+
+	// 返回的区域坐标所用的坐标系
+	CoordSystem() CoordSystem
+
+	// 获得距离指定经纬度最近的区域，坐标缺失的区域不参与查找
+	// lat, lng：纬度、经度
+	// level：区域层级，AnyLevel为不限层级
+	// opts：输入经纬度的坐标系，见DefaultSpatialOpt
+	NearestArea(lat, lng float64, level AreaLevel, opts ...SpatialOpt) (NearbyArea, error)
+
+	// 获得距离指定经纬度最近的k个区域，按距离从近到远排列
+	// lat, lng：纬度、经度
+	// k：返回的区域数
+	// level：区域层级，AnyLevel为不限层级
+	// opts：输入经纬度的坐标系，见DefaultSpatialOpt
+	KNearest(lat, lng float64, k int, level AreaLevel, opts ...SpatialOpt) ([]NearbyArea, error)
+
+	// 获得距离指定经纬度不超过km公里的区域，按距离从近到远排列
+	// lat, lng：纬度、经度
+	// km：半径，单位公里
+	// level：区域层级，AnyLevel为不限层级
+	// opts：输入经纬度的坐标系，见DefaultSpatialOpt
+	AreasWithinRadius(lat, lng, km float64, level AreaLevel, opts ...SpatialOpt) ([]NearbyArea, error)
+
+	// 获得坐标位于指定经纬度范围内的区域，按与范围中心的距离从近到远排列
+	// minLat, minLng, maxLat, maxLng：纬度、经度范围（含边界）
+	// level：区域层级，AnyLevel为不限层级
+	// opts：输入经纬度的坐标系，见DefaultSpatialOpt
+	AreasInBBox(minLat, minLng, maxLat, maxLng float64, level AreaLevel, opts ...SpatialOpt) ([]NearbyArea, error)
+
+	// 获得指定区域的边界，需通过DefaultOpt.LoadBoundaryFromFile等加载边界数据，
+	// 区域没有边界数据时返回ErrBoundaryNotFound
+	Boundary(code AreaCode) (*Boundary, error)
+
+	// 根据边界数据获得包含指定经纬度的各层级区域，按层级从高到低排列
+	// lat, lng：纬度、经度
+	// opts：输入经纬度的坐标系，见DefaultSpatialOpt
+	Locate(lat, lng float64, opts ...SpatialOpt) ([]AreaData, error)
+
+	// 指定区域的边界是否包含指定经纬度
+	// code：指定区域Code
+	// lat, lng：纬度、经度
+	// opts：输入经纬度的坐标系，见DefaultSpatialOpt
+	Contains(code AreaCode, lat, lng float64, opts ...SpatialOpt) (bool, error)
+
+	// 获得指定区域边界的面积，单位平方公里
+	// code：指定区域Code
+	PolygonArea(code AreaCode) (float64, error)
+
+	// 数据版本，使用内置数据时为BuildinVersion，通过DefaultOpt.SetHistory加载时为指定的版本，其他数据源为空
+	Version() string
+
+	// 沿区划代码变更记录查找代码在当前数据版本中对应的代码，仅使用不晚于Version()的变更记录，
+	// Version()为空时使用全部变更记录，此时返回的代码可能不在已加载的数据中，见ChangeLog.Resolve
+	// code：区划代码，可为已撤销或变更的旧代码
+	ResolveCode(code AreaCode) ([]AreaCode, error)
+
 	// 获得指定区域Code的区域信息
 	// code：指定区域Code
 	// withSub： 是否遍历子区域
@@ -55,34 +136,99 @@ type AreaService interface {
 	// code：指定区域Code
 	// recursion： 是否遍历所有父区域
 	ParentAreaByCode(code AreaCode, recursion bool) (Area, error)
+
+	// 校验区域数据，返回校验报告
+	Validate() (*ValidateReport, error)
+
+	// 按名称搜索区域，返回按匹配得分排序的结果
+	// keyword：搜索关键字
+	// opts：匹配方式、层级、上级区域等过滤条件，见DefaultSearchOpt
+	Search(keyword string, opts ...SearchOpt) ([]SearchResult, error)
 }
 
 type defaultAreaService struct {
 	ds     DataSource
+	bs     BoundarySource
 	areas  [][]AreaData
 	levels []AreaLevel
+	strict bool
+	// 以子区域坐标填充缺失坐标
+	centroid bool
+	// 数据源的坐标系
+	dataCoord CoordSystem
+	// 返回的坐标系，为空时与数据源相同
+	coord CoordSystem
+	// 数据版本
+	version string
+	// 区划代码变更记录
+	changes ChangeLog
+	// CSV数据源的列布局，见DefaultOpt.LoadFromCSVFile
+	csvLayout *csvLayoutHolder
+
+	// code -> 区域数据
+	codeIndex map[AreaCode]AreaData
+	// parentCode -> 子区域数据
+	childIndex map[AreaCode][]AreaData
+	// 各层级区域的空间索引
+	spatialIndex []*kdTree
+	// 所有区域的空间索引
+	spatialAll *kdTree
+	// code -> 区域边界
+	boundaries map[AreaCode]*Boundary
+	// 各层级有边界数据的区域
+	boundaryLevels [][]AreaData
 }
 
 type Opt func(s *defaultAreaService)
 
+// 创建区域服务，加载失败时返回nil，需要错误信息请使用NewAreaServiceE
 func NewAreaService(opts ...Opt) *defaultAreaService {
-	ret := &defaultAreaService{
-		ds: buildinDataSource,
-	}
-	for _, opt := range opts {
-		opt(ret)
-	}
-	err := ret.parse()
+	ret, err := newAreaService(opts...)
 	if err != nil {
 		return nil
 	}
 	return ret
 }
 
+// 从文件创建区域服务，加载失败时返回nil，需要错误信息请使用NewAreaServiceFromFileE
 func NewAreaServiceFromFile(path string) *defaultAreaService {
 	return NewAreaService(DefaultOpt.LoadFromFile(path))
 }
 
+// 创建区域服务，加载失败时返回错误
+func NewAreaServiceE(opts ...Opt) (AreaService, error) {
+	ret, err := newAreaService(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// 从文件创建区域服务，加载失败时返回错误：
+// 文件读取失败可用errors.Is(err, os.ErrNotExist)等判断，数据格式错误可用errors.Is(err, ErrInvalidData)判断
+func NewAreaServiceFromFileE(path string) (AreaService, error) {
+	return NewAreaServiceE(DefaultOpt.LoadFromFile(path))
+}
+
+func newAreaService(opts ...Opt) (*defaultAreaService, error) {
+	ret := &defaultAreaService{
+		dataCoord: BuildinCoordSystem,
+		changes:   buildinChanges,
+	}
+	for _, opt := range opts {
+		opt(ret)
+	}
+	if ret.ds == nil {
+		ret.ds = buildinDataSource
+		ret.version = BuildinVersion
+	}
+	err := ret.parse()
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
 func (s *defaultAreaService) Data() ([]AreaData, error) {
 	return s.ds()
 }
@@ -137,61 +283,59 @@ func (s *defaultAreaService) AreaByName(name string, withSub bool) ([]Area, erro
 }
 
 func (s *defaultAreaService) AreaByCode(code AreaCode, withSub bool) (Area, error) {
-	for _, lv := range s.areas {
-		for _, ad := range lv {
-			if ad.Code == code {
-				sub := Area{
-					AreaData: ad,
-				}
-				if withSub {
-					_ = s.getChildren(&sub, true)
-				}
-				return sub, nil
-			}
-		}
+	ad, ok := s.codeIndex[code.Normalize()]
+	if !ok {
+		return Area{}, notFoundError(code)
+	}
+	sub := Area{
+		AreaData: ad,
 	}
-	return Area{}, fmt.Errorf("Area with code %v not found. ", code)
+	if withSub {
+		_ = s.getChildren(&sub, true)
+	}
+	return sub, nil
 }
 
 func (s *defaultAreaService) checkLevel(level int) error {
 	if level < TopLevelInt || level > len(s.areas) {
-		return fmt.Errorf("Level %d out of range. ", level)
+		return levelError(Int2AreaLevel(level))
 	}
 	return nil
 }
 
 func (s *defaultAreaService) SubareaByCode(code AreaCode, recursion bool) ([]Area, error) {
-	for _, lv := range s.areas {
-		for _, ad := range lv {
-			if ad.Code == code {
-				sub := Area{
-					AreaData: ad,
-				}
-				err := s.getChildren(&sub, recursion)
-				return sub.Subareas, err
-			}
-		}
+	ad, ok := s.codeIndex[code.Normalize()]
+	if !ok {
+		return nil, notFoundError(code)
+	}
+	sub := Area{
+		AreaData: ad,
 	}
-	return nil, fmt.Errorf("Area with code %v not found. ", code)
+	err := s.getChildren(&sub, recursion)
+	return sub.Subareas, err
 }
 
 func (s *defaultAreaService) ParentAreaByCode(code AreaCode, recursion bool) (Area, error) {
-	for i := len(s.areas) - 1; i >= 0; i-- {
-		lv := s.areas[i]
-		for _, ad := range lv {
-			if ad.Code == code {
-				parent := &Area{
-					AreaData: ad,
-				}
-				p, err := s.getParent(parent, recursion)
-				if p == nil {
-					return Area{}, err
-				}
-				return *p, err
-			}
-		}
+	ad, ok := s.codeIndex[code.Normalize()]
+	if !ok {
+		return Area{}, notFoundError(code)
+	}
+	parent := &Area{
+		AreaData: ad,
+	}
+	p, err := s.getParent(parent, recursion)
+	if p == nil {
+		return Area{}, err
+	}
+	return *p, err
+}
+
+func (s *defaultAreaService) Validate() (*ValidateReport, error) {
+	d, err := s.Data()
+	if err != nil {
+		return nil, err
 	}
-	return Area{}, fmt.Errorf("Area with code %v not found. ", code)
+	return ValidateData(d), nil
 }
 
 func (s *defaultAreaService) parse() error {
@@ -199,17 +343,67 @@ func (s *defaultAreaService) parse() error {
 	if err != nil {
 		return err
 	}
-	s.areas = make([][]AreaData, 0, 3)
+	if s.strict {
+		if err := ValidateData(d).Err(); err != nil {
+			return err
+		}
+	}
+	// 先确定最大层级，使数据顺序及层级缺失不影响解析
+	maxLevel := 0
 	for _, area := range d {
-		lv := area.Level.Int()
-		if s.AreaLevelNumber() < lv {
-			lv := make([]AreaData, 0, 32)
-			s.areas = append(s.areas, lv)
-			s.levels = append(s.levels, area.Level)
+		lv, err := area.Level.ParseInt()
+		if err != nil {
+			return &AreaError{
+				Err:   ErrInvalidData,
+				Code:  area.Code,
+				Level: area.Level,
+				Cause: err,
+			}
+		}
+		if lv > maxLevel {
+			maxLevel = lv
 		}
+	}
+	if s.coord == "" {
+		s.coord = s.dataCoord
+	}
+	if !s.dataCoord.IsValid() || !s.coord.IsValid() {
+		return &AreaError{
+			Err:   ErrInvalidCoordinate,
+			Cause: fmt.Errorf("unsupported coordinate system conversion %s -> %s", s.dataCoord, s.coord),
+		}
+	}
+	// 统一12位统计用区划代码与6位、9位代码，同时避免修改数据源的数据
+	d = normalizeData(d)
+	convertCoordinates(d, s.dataCoord, s.coord)
+	// 在填充坐标前校验，可疑坐标不参与坐标填充及空间索引
+	suspect := suspectCoordinates(d)
+	if s.centroid {
+		fillCentroids(d, suspect)
+	}
+	fillPinyin(d)
+	s.areas = make([][]AreaData, maxLevel)
+	s.levels = nil
+	s.codeIndex = make(map[AreaCode]AreaData, len(d))
+	s.childIndex = make(map[AreaCode][]AreaData, len(d)/8)
+	for _, area := range d {
+		lv := area.Level.Int()
 		s.areas[lv-1] = append(s.areas[lv-1], area)
+		s.codeIndex[area.Code] = area
 	}
-	return nil
+	// 仅索引层级低于父区域的子区域，避免错误的ParentCode（如指向自身或成环）导致遍历无法结束
+	for _, area := range d {
+		if _, ok := s.parentArea(area); ok {
+			s.childIndex[area.ParentCode] = append(s.childIndex[area.ParentCode], area)
+		}
+	}
+	for i := range s.areas {
+		if len(s.areas[i]) > 0 {
+			s.levels = append(s.levels, Int2AreaLevel(i+1))
+		}
+	}
+	s.buildSpatialIndex(suspect)
+	return s.loadBoundaries()
 }
 
 func (s *defaultAreaService) getChildren(area *Area, recursion bool) error {
@@ -221,16 +415,19 @@ func (s *defaultAreaService) getChildren(area *Area, recursion bool) error {
 	if lv == len(s.areas) {
 		return nil
 	}
-	for _, a := range s.areas[lv] {
-		if a.ParentCode == area.Code {
-			sub := Area{
-				AreaData: a,
-			}
-			if recursion {
-				_ = s.getChildren(&sub, recursion)
-			}
-			area.Subareas = append(area.Subareas, sub)
+	children := s.childIndex[area.Code]
+	if len(children) == 0 {
+		return nil
+	}
+	area.Subareas = make([]Area, 0, len(children))
+	for _, a := range children {
+		sub := Area{
+			AreaData: a,
+		}
+		if recursion {
+			_ = s.getChildren(&sub, recursion)
 		}
+		area.Subareas = append(area.Subareas, sub)
 	}
 	return nil
 }
@@ -244,23 +441,29 @@ func (s *defaultAreaService) getParent(area *Area, recursion bool) (*Area, error
 	if area.Level == TopLevel {
 		return area, nil
 	}
-	lv -= 2
-	parent := area
-	for _, a := range s.areas[lv] {
-		if a.Code == area.ParentCode {
-			parent = &Area{
-				AreaData: a,
-				Subareas: []Area{*area},
-			}
-			if recursion {
-				parent, _ = s.getParent(parent, recursion)
-			}
-			return parent, nil
-		}
+	a, ok := s.parentArea(area.AreaData)
+	if !ok {
+		return area, nil
+	}
+	parent := &Area{
+		AreaData: a,
+		Subareas: []Area{*area},
+	}
+	if recursion {
+		parent, _ = s.getParent(parent, recursion)
 	}
 	return parent, nil
 }
 
+// 获得父区域，父区域不存在或层级不高于该区域时返回false
+func (s *defaultAreaService) parentArea(ad AreaData) (AreaData, bool) {
+	p, ok := s.codeIndex[ad.ParentCode]
+	if !ok || p.Level.Int() >= ad.Level.Int() {
+		return AreaData{}, false
+	}
+	return p, true
+}
+
 type defaultOption struct{}
 
 var DefaultOpt defaultOption
@@ -271,18 +474,44 @@ func buildinDataSource() ([]AreaData, error) {
 	return loadFromData([]byte(static.Areas))
 }
 
-func loadFromData(data []byte) ([]AreaData, error) {
-	var ret []AreaData
-	err := json.Unmarshal(data, &ret)
-	return ret, err
-}
-
 func (opt defaultOption) SetDataSource(ds DataSource) Opt {
 	return func(s *defaultAreaService) {
 		s.ds = ds
 	}
 }
 
+// 严格模式：数据校验存在错误时加载失败，见ValidateData
+func (opt defaultOption) Strict() Opt {
+	return func(s *defaultAreaService) {
+		s.strict = true
+	}
+}
+
+// 数据源（含边界数据）的坐标系，默认为内置数据的坐标系BuildinCoordSystem
+func (opt defaultOption) DataCoordSystem(cs CoordSystem) Opt {
+	return func(s *defaultAreaService) {
+		s.dataCoord = cs
+	}
+}
+
+// 区域服务返回的坐标系，区域坐标及边界在加载时转换，不影响Data()返回的原始数据；默认与数据源相同
+func (opt defaultOption) CoordSystem(cs CoordSystem) Opt {
+	return func(s *defaultAreaService) {
+		s.coord = cs
+	}
+}
+
+func (s *defaultAreaService) CoordSystem() CoordSystem {
+	return s.coord
+}
+
+// 以子区域坐标的平均值填充缺失坐标的区域（如市辖区），不影响Data()返回的原始数据
+func (opt defaultOption) FillCentroid() Opt {
+	return func(s *defaultAreaService) {
+		s.centroid = true
+	}
+}
+
 func (opt defaultOption) LoadFromFile(path string) Opt {
 	return func(s *defaultAreaService) {
 		s.ds = func() (data []AreaData, e error) {
@@ -294,3 +523,45 @@ func (opt defaultOption) LoadFromFile(path string) Opt {
 		}
 	}
 }
+
+// 从io.Reader加载JSON格式的区域数据，首次加载时读取全部内容并缓存，之后Data()等重复读取使用缓存内容
+func (opt defaultOption) LoadFromReader(r io.Reader) Opt {
+	return func(s *defaultAreaService) {
+		var (
+			once sync.Once
+			d    []byte
+			err  error
+		)
+		s.ds = func() ([]AreaData, error) {
+			once.Do(func() {
+				d, err = io.ReadAll(r)
+			})
+			if err != nil {
+				return nil, err
+			}
+			return loadFromData(d)
+		}
+	}
+}
+
+// 从fs.FS（如embed.FS）中的文件加载JSON格式的区域数据
+func (opt defaultOption) LoadFromFS(fsys fs.FS, name string) Opt {
+	return func(s *defaultAreaService) {
+		s.ds = func() ([]AreaData, error) {
+			d, err := fs.ReadFile(fsys, name)
+			if err != nil {
+				return nil, err
+			}
+			return loadFromData(d)
+		}
+	}
+}
+
+// 从JSON格式的字节数据加载区域数据，加载后不应再修改data
+func (opt defaultOption) LoadFromBytes(data []byte) Opt {
+	return func(s *defaultAreaService) {
+		s.ds = func() ([]AreaData, error) {
+			return loadFromData(data)
+		}
+	}
+}
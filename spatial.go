@@ -0,0 +1,228 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description: 根据经纬度查找最近的区域及范围内的区域
+
+package carea
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// 不限层级
+const AnyLevel = AreaLevel("")
+
+type NearbyArea struct {
+	AreaData
+	// 与查询点的球面距离，单位公里；AreasInBBox中为与范围中心的距离
+	Distance float64 `json:"distance"`
+	// 从顶级区域到该区域的路径
+	Path []AreaData `json:"path"`
+}
+
+// 构建空间索引，坐标缺失、超出ChinaBounds、校验发现坐标可疑的区域及占位区域（如市辖区）不参与索引，
+// 占位区域的下级区域仍参与索引
+func (s *defaultAreaService) buildSpatialIndex(suspect map[AreaCode]bool) {
+	all := make([]kdPoint, 0, len(s.codeIndex))
+	s.spatialIndex = make([]*kdTree, len(s.areas))
+	for i, lv := range s.areas {
+		points := make([]kdPoint, 0, len(lv))
+		for _, ad := range lv {
+			lat, lng, ok := ad.Location()
+			if !ok || !inChina(lat, lng) || suspect[ad.Code] || ad.IsPlaceholder() {
+				continue
+			}
+			points = append(points, kdPoint{pos: toCartesian(lat, lng), area: ad})
+		}
+		all = append(all, points...)
+		s.spatialIndex[i] = newKdTree(points)
+	}
+	s.spatialAll = newKdTree(all)
+}
+
+// 校验发现坐标超出范围或与上级区域相距过远的区域，见ValidateData
+func suspectCoordinates(data []AreaData) map[AreaCode]bool {
+	ret := map[AreaCode]bool{}
+	for _, i := range ValidateData(data).Issues {
+		if i.Type == IssueOutOfBounds || i.Type == IssueFarFromParent {
+			ret[i.Code] = true
+		}
+	}
+	return ret
+}
+
+type spatialConfig struct {
+	coord CoordSystem
+}
+
+type SpatialOpt func(c *spatialConfig)
+
+type defaultSpatialOption struct{}
+
+var DefaultSpatialOpt defaultSpatialOption
+
+// 输入经纬度的坐标系，默认与区域服务返回的坐标系相同，见DefaultOpt.CoordSystem
+func (opt defaultSpatialOption) CoordSystem(cs CoordSystem) SpatialOpt {
+	return func(c *spatialConfig) {
+		c.coord = cs
+	}
+}
+
+// 校验输入经纬度并转换为区域服务的坐标系
+func (s *defaultAreaService) inputCoordinate(lat, lng float64, opts []SpatialOpt) (float64, float64, error) {
+	if err := checkCoordinate(lat, lng); err != nil {
+		return 0, 0, err
+	}
+	conf := spatialConfig{
+		coord: s.coord,
+	}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	return ConvertCoordinate(lat, lng, conf.coord, s.coord)
+}
+
+func (s *defaultAreaService) NearestArea(lat, lng float64, level AreaLevel, opts ...SpatialOpt) (NearbyArea, error) {
+	ret, err := s.KNearest(lat, lng, 1, level, opts...)
+	if err != nil {
+		return NearbyArea{}, err
+	}
+	return ret[0], nil
+}
+
+func (s *defaultAreaService) KNearest(lat, lng float64, k int, level AreaLevel, opts ...SpatialOpt) ([]NearbyArea, error) {
+	lat, lng, err := s.inputCoordinate(lat, lng, opts)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := s.spatialTree(level)
+	if err != nil {
+		return nil, err
+	}
+	if len(tree.points) == 0 {
+		return nil, &AreaError{
+			Err:   ErrAreaNotFound,
+			Level: level,
+		}
+	}
+	found := tree.nearest(toCartesian(lat, lng), k)
+	ret := make([]NearbyArea, len(found))
+	for i, r := range found {
+		ret[i] = s.nearbyArea(r.point.area, lat, lng)
+	}
+	return ret, nil
+}
+
+func (s *defaultAreaService) AreasWithinRadius(lat, lng, km float64, level AreaLevel, opts ...SpatialOpt) ([]NearbyArea, error) {
+	lat, lng, err := s.inputCoordinate(lat, lng, opts)
+	if err != nil {
+		return nil, err
+	}
+	if km < 0 || math.IsNaN(km) {
+		return nil, &AreaError{
+			Err:   ErrInvalidCoordinate,
+			Cause: fmt.Errorf("invalid radius %v", km),
+		}
+	}
+	tree, err := s.spatialTree(level)
+	if err != nil {
+		return nil, err
+	}
+	// 球面距离对应的弦长，半径超过半个地球周长时包含所有区域
+	chord := 2.0
+	if angle := km / earthRadiusKm; angle < math.Pi {
+		chord = 2 * math.Sin(angle/2)
+	}
+	found := tree.within(toCartesian(lat, lng), chord*chord)
+	ret := make([]NearbyArea, 0, len(found))
+	for _, r := range found {
+		a := s.nearbyArea(r.point.area, lat, lng)
+		// 弦长换算存在浮点误差，以haversine距离为准
+		if a.Distance <= km {
+			ret = append(ret, a)
+		}
+	}
+	sortNearbyAreas(ret)
+	return ret, nil
+}
+
+func (s *defaultAreaService) AreasInBBox(minLat, minLng, maxLat, maxLng float64, level AreaLevel, opts ...SpatialOpt) ([]NearbyArea, error) {
+	// 坐标系偏移量远小于常见的范围大小，仅转换两个角点
+	minLat, minLng, err := s.inputCoordinate(minLat, minLng, opts)
+	if err != nil {
+		return nil, err
+	}
+	maxLat, maxLng, err = s.inputCoordinate(maxLat, maxLng, opts)
+	if err != nil {
+		return nil, err
+	}
+	if minLat > maxLat || minLng > maxLng {
+		return nil, &AreaError{
+			Err:   ErrInvalidCoordinate,
+			Cause: fmt.Errorf("invalid bounding box (%v, %v, %v, %v)", minLat, minLng, maxLat, maxLng),
+		}
+	}
+	tree, err := s.spatialTree(level)
+	if err != nil {
+		return nil, err
+	}
+	centerLat, centerLng := (minLat+maxLat)/2, (minLng+maxLng)/2
+	var ret []NearbyArea
+	for _, p := range tree.points {
+		lat, lng, _ := p.area.Location()
+		if lat < minLat || lat > maxLat || lng < minLng || lng > maxLng {
+			continue
+		}
+		ret = append(ret, s.nearbyArea(p.area, centerLat, centerLng))
+	}
+	sortNearbyAreas(ret)
+	return ret, nil
+}
+
+// 按距离从近到远排序，距离相同时Code小者优先
+func sortNearbyAreas(ret []NearbyArea) {
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Distance != ret[j].Distance {
+			return ret[i].Distance < ret[j].Distance
+		}
+		return ret[i].Code < ret[j].Code
+	})
+}
+
+func (s *defaultAreaService) spatialTree(level AreaLevel) (*kdTree, error) {
+	if level == AnyLevel {
+		return s.spatialAll, nil
+	}
+	lv := level.Int()
+	if err := s.checkLevel(lv); err != nil {
+		return nil, err
+	}
+	return s.spatialIndex[lv-1], nil
+}
+
+func (s *defaultAreaService) nearbyArea(ad AreaData, lat, lng float64) NearbyArea {
+	alat, alng, _ := ad.Location()
+	path, _ := s.Path(ad.Code)
+	return NearbyArea{
+		AreaData: ad,
+		Distance: haversine(lat, lng, alat, alng),
+		Path:     path,
+	}
+}
+
+func inChina(lat, lng float64) bool {
+	b := ChinaBounds
+	return lat >= b.MinLatitude && lat <= b.MaxLatitude && lng >= b.MinLongitude && lng <= b.MaxLongitude
+}
+
+func checkCoordinate(lat, lng float64) error {
+	if !isFinite(lat) || !isFinite(lng) || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return &AreaError{
+			Err:   ErrInvalidCoordinate,
+			Cause: fmt.Errorf("coordinate (%v, %v) out of range", lat, lng),
+		}
+	}
+	return nil
+}
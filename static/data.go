@@ -2,6 +2,10 @@ package static
 
 import "strconv"
 
+// Areas中经纬度的坐标系：城市坐标与百度地图的城市中心点坐标一致，
+// 如北京市(39.929986, 116.395645)、上海市(31.249162, 121.487899)、成都市(30.679943, 104.067923)，为百度BD-09坐标
+const AreasCoordSystem = "BD-09"
+
 const Areas = `[
 {"code":"110000","parentCode":"0","level":"1","name":"北京市","latitude":"39.929986","longitude":"116.395645"},
 {"code":"110100","parentCode":"110000","level":"2","name":"市辖区","latitude":"","longitude":""},
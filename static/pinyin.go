@@ -0,0 +1,129 @@
+package static
+
+// 区域名称用字的拼音，不带声调，ü写作v，多音字取地名中的常用读音
+var Pinyin = map[rune]string{
+	'阿': "a", '爱': "ai", '安': "an", '岸': "an", '庵': "an", '鞍': "an", '昂': "ang", '敖': "ao", '澳': "ao", '八': "ba",
+	'坝': "ba", '巴': "ba", '灞': "ba", '霸': "ba", '鲅': "ba", '拜': "bai", '柏': "bai", '白': "bai", '百': "bai", '坂': "ban",
+	'版': "ban", '班': "ban", '蚌': "bang", '保': "bao", '包': "bao", '堡': "bao", '宝': "bao", '北': "bei", '碑': "bei", '碚': "bei",
+	'贝': "bei", '陂': "bei", '本': "ben", '壁': "bi", '比': "bi", '毕': "bi", '濞': "bi", '璧': "bi", '碧': "bi", '边': "bian",
+	'别': "bie", '宾': "bin", '彬': "bin", '滨': "bin", '兵': "bing", '秉': "bing", '亳': "bo", '伯': "bo", '勃': "bo", '博': "bo",
+	'波': "bo", '埔': "bu", '埠': "bu", '布': "bu", '步': "bu", '部': "bu", '彩': "cai", '蔡': "cai", '仓': "cang", '沧': "cang",
+	'苍': "cang", '曹': "cao", '草': "cao", '册': "ce", '策': "ce", '岑': "cen", '察': "cha", '岔': "cha", '查': "cha", '茶': "cha",
+	'瀍': "chan", '禅': "chan", '厂': "chang", '场': "chang", '常': "chang", '昌': "chang", '长': "chang", '巢': "chao", '朝': "chao", '潮': "chao",
+	'车': "che", '辰': "chen", '郴': "chen", '陈': "chen", '呈': "cheng", '城': "cheng", '成': "cheng", '承': "cheng", '澄': "cheng", '称': "cheng",
+	'坻': "chi", '池': "chi", '茌': "chi", '赤': "chi", '充': "chong", '冲': "chong", '崇': "chong", '畴': "chou", '楚': "chu", '滁': "chu",
+	'川': "chuan", '船': "chuan", '春': "chun", '淳': "chun", '慈': "ci", '次': "ci", '磁': "ci", '丛': "cong", '从': "cong", '枞': "cong",
+	'翠': "cui", '村': "cun", '措': "cuo", '错': "cuo", '大': "da", '达': "da", '代': "dai", '傣': "dai", '岱': "dai", '带': "dai",
+	'戴': "dai", '丹': "dan", '儋': "dan", '单': "dan", '郸': "dan", '宕': "dang", '当': "dang", '砀': "dang", '刀': "dao", '岛': "dao",
+	'稻': "dao", '道': "dao", '得': "de", '德': "de", '灯': "deng", '登': "deng", '磴': "deng", '等': "deng", '邓': "deng", '地': "di",
+	'底': "di", '棣': "di", '滴': "di", '的': "di", '迪': "di", '垫': "dian", '店': "dian", '淀': "dian", '点': "dian", '电': "dian",
+	'甸': "dian", '调': "diao", '叠': "die", '迭': "die", '丁': "ding", '定': "ding", '顶': "ding", '鼎': "ding", '东': "dong", '侗': "dong",
+	'峒': "dong", '洞': "dong", '斗': "dou", '度': "du", '杜': "du", '渡': "du", '独': "du", '都': "du", '端': "duan", '堆': "dui",
+	'敦': "dun", '多': "duo", '掇': "duo", '峨': "e", '鄂': "e", '额': "e", '恩': "en", '二': "er", '儿': "er", '尔': "er",
+	'洱': "er", '法': "fa", '樊': "fan", '烦': "fan", '番': "fan", '繁': "fan", '范': "fan", '坊': "fang", '房': "fang", '放': "fang",
+	'方': "fang", '邡': "fang", '防': "fang", '妃': "fei", '肥': "fei", '费': "fei", '分': "fen", '汾': "fen", '芬': "fen", '丰': "feng",
+	'凤': "feng", '奉': "feng", '封': "feng", '峰': "feng", '烽': "feng", '锋': "feng", '风': "feng", '佛': "fo", '复': "fu", '孚': "fu",
+	'富': "fu", '府': "fu", '扶': "fu", '抚': "fu", '浮': "fu", '涪': "fu", '福': "fu", '罘': "fu", '芙': "fu", '阜': "fu",
+	'附': "fu", '嘎': "ga", '噶': "ga", '改': "gai", '盖': "gai", '干': "gan", '感': "gan", '甘': "gan", '赣': "gan", '冈': "gang",
+	'刚': "gang", '岗': "gang", '港': "gang", '钢': "gang", '皋': "gao", '藁': "gao", '高': "gao", '个': "ge", '仡': "ge", '各': "ge",
+	'戈': "ge", '格': "ge", '葛': "ge", '阁': "ge", '革': "ge", '根': "gen", '耿': "geng", '公': "gong", '共': "gong", '功': "gong",
+	'宫': "gong", '工': "gong", '巩': "gong", '弓': "gong", '恭': "gong", '拱': "gong", '珙': "gong", '贡': "gong", '沟': "gou", '古': "gu",
+	'固': "gu", '姑': "gu", '故': "gu", '沽': "gu", '谷': "gu", '鼓': "gu", '瓜': "gua", '拐': "guai", '关': "guan", '冠': "guan",
+	'官': "guan", '灌': "guan", '管': "guan", '莞': "guan", '观': "guan", '馆': "guan", '光': "guang", '广': "guang", '归': "gui", '桂': "gui",
+	'贵': "gui", '国': "guo", '果': "guo", '郭': "guo", '哈': "ha", '海': "hai", '含': "han", '寒': "han", '汉': "han", '涵': "han",
+	'罕': "han", '邗': "han", '邯': "han", '韩': "han", '杭': "hang", '好': "hao", '浩': "hao", '濠': "hao", '合': "he", '和': "he",
+	'河': "he", '禾': "he", '荷': "he", '菏': "he", '贺': "he", '赫': "he", '鹤': "he", '黑': "hei", '亨': "heng", '恒': "heng",
+	'横': "heng", '衡': "heng", '宏': "hong", '洪': "hong", '红': "hong", '虹': "hong", '侯': "hou", '后': "hou", '互': "hu", '呼': "hu",
+	'壶': "hu", '户': "hu", '湖': "hu", '祜': "hu", '胡': "hu", '葫': "hu", '虎': "hu", '划': "hua", '化': "hua", '华': "hua",
+	'桦': "hua", '滑': "hua", '花': "hua", '骅': "hua", '怀': "huai", '槐': "huai", '淮': "huai", '桓': "huan", '环': "huan", '凰': "huang",
+	'晃': "huang", '湟': "huang", '潢': "huang", '煌': "huang", '皇': "huang", '黄': "huang", '会': "hui", '回': "hui", '徽': "hui", '惠': "hui",
+	'晖': "hui", '汇': "hui", '珲': "hui", '辉': "hui", '浑': "hun", '获': "huo", '霍': "huo", '冀': "ji", '即': "ji", '及': "ji",
+	'吉': "ji", '基': "ji", '暨': "ji", '极': "ji", '济': "ji", '积': "ji", '稷': "ji", '级': "ji", '绩': "ji", '蓟': "ji",
+	'集': "ji", '鸡': "ji", '伽': "jia", '佳': "jia", '加': "jia", '嘉': "jia", '夹': "jia", '家': "jia", '架': "jia", '茄': "jia",
+	'贾': "jia", '迦': "jia", '郏': "jia", '剑': "jian", '尖': "jian", '建': "jian", '涧': "jian", '犍': "jian", '监': "jian", '碱': "jian",
+	'简': "jian", '箭': "jian", '间': "jian", '姜': "jiang", '将': "jiang", '江': "jiang", '疆': "jiang", '绛': "jiang", '交': "jiao", '椒': "jiao",
+	'焦': "jiao", '礁': "jiao", '胶': "jiao", '蕉': "jiao", '蛟': "jiao", '郊': "jiao", '介': "jie", '揭': "jie", '界': "jie", '结': "jie",
+	'节': "jie", '街': "jie", '解': "jie", '晋': "jin", '津': "jin", '缙': "jin", '进': "jin", '金': "jin", '锦': "jin", '井': "jing",
+	'京': "jing", '旌': "jing", '景': "jing", '泾': "jing", '精': "jing", '经': "jing", '荆': "jing", '镜': "jing", '靖': "jing", '静': "jing",
+	'久': "jiu", '九': "jiu", '旧': "jiu", '酒': "jiu", '鸠': "jiu", '句': "ju", '居': "ju", '巨': "ju", '莒': "ju", '鄄': "juan",
+	'觉': "jue", '军': "jun", '君': "jun", '峻': "jun", '浚': "jun", '卡': "ka", '喀': "ka", '凯': "kai", '开': "kai", '坎': "kan",
+	'康': "kang", '考': "kao", '克': "ke", '岢': "ke", '柯': "ke", '科': "ke", '垦': "ken", '崆': "kong", '口': "kou", '库': "ku",
+	'宽': "kuan", '矿': "kuang", '奎': "kui", '坤': "kun", '昆': "kun", '喇': "la", '拉': "la", '腊': "la", '崃': "lai", '来': "lai",
+	'涞': "lai", '莱': "lai", '赉': "lai", '兰': "lan", '岚': "lan", '澜': "lan", '蓝': "lan", '廊': "lang", '朗': "lang", '浪': "lang",
+	'琅': "lang", '蒗': "lang", '郎': "lang", '阆': "lang", '佬': "lao", '崂': "lao", '老': "lao", '乐': "le", '勒': "le", '垒': "lei",
+	'类': "lei", '耒': "lei", '雷': "lei", '冷': "leng", '棱': "leng", '楞': "leng", '丽': "li", '傈': "li", '利': "li", '力': "li",
+	'历': "li", '李': "li", '栗': "li", '梨': "li", '溧': "li", '澧': "li", '犁': "li", '理': "li", '礼': "li", '离': "li",
+	'立': "li", '荔': "li", '蠡': "li", '醴': "li", '里': "li", '鲤': "li", '黎': "li", '廉': "lian", '涟': "lian", '联': "lian",
+	'莲': "lian", '连': "lian", '两': "liang", '凉': "liang", '梁': "liang", '良': "liang", '聊': "liao", '辽': "liao", '列': "lie", '烈': "lie",
+	'临': "lin", '林': "lin", '蔺': "lin", '邻': "lin", '麟': "lin", '令': "ling", '凌': "ling", '岭': "ling", '灵': "ling", '陵': "ling",
+	'零': "ling", '六': "liu", '柳': "liu", '流': "liu", '浏': "liu", '留': "liu", '陇': "long", '隆': "long", '龙': "long", '娄': "lou",
+	'楼': "lou", '卢': "lu", '庐': "lu", '泸': "lu", '潞': "lu", '炉': "lu", '碌': "lu", '禄': "lu", '芦': "lu", '路': "lu",
+	'陆': "lu", '鲁': "lu", '鹿': "lu", '麓': "lu", '峦': "luan", '栾': "luan", '滦': "luan", '仑': "lun", '伦': "lun", '轮': "lun",
+	'洛': "luo", '漯': "luo", '罗': "luo", '萝': "luo", '吕': "lv", '旅': "lv", '绿': "lv", '略': "lve", '玛': "ma", '马': "ma",
+	'麻': "ma", '迈': "mai", '麦': "mai", '曼': "man", '满': "man", '芒': "mang", '毛': "mao", '茂': "mao", '茅': "mao", '梅': "mei",
+	'湄': "mei", '眉': "mei", '美': "mei", '们': "men", '门': "men", '勐': "meng", '孟': "meng", '梦': "meng", '盟': "meng", '蒙': "meng",
+	'密': "mi", '弥': "mi", '汨': "mi", '泌': "mi", '米': "mi", '冕': "mian", '勉': "mian", '棉': "mian", '渑': "mian", '绵': "mian",
+	'苗': "miao", '岷': "min", '敏': "min", '民': "min", '闵': "min", '闽': "min", '名': "ming", '明': "ming", '鸣': "ming", '墨': "mo",
+	'末': "mo", '漠': "mo", '磨': "mo", '莫': "mo", '谟': "mo", '默': "mo", '牟': "mou", '谋': "mou", '仫': "mu", '木': "mu",
+	'沐': "mu", '牡': "mu", '牧': "mu", '穆': "mu", '纳': "na", '那': "na", '乃': "nai", '奈': "nai", '南': "nan", '囊': "nang",
+	'淖': "nao", '讷': "ne", '内': "nei", '嫩': "nen", '尼': "ni", '年': "nian", '碾': "nian", '聂': "nie", '宁': "ning", '牛': "niu",
+	'农': "nong", '怒': "nu", '诺': "nuo", '瓯': "ou", '牌': "pai", '攀': "pan", '潘': "pan", '盘': "pan", '磐': "pan", '沛': "pei",
+	'彭': "peng", '蓬': "peng", '皮': "pi", '邳': "pi", '郫': "pi", '偏': "pian", '票': "piao", '凭': "ping", '坪': "ping", '屏': "ping",
+	'平': "ping", '萍': "ping", '坡': "po", '泊': "po", '鄱': "po", '颇': "po", '仆': "pu", '普': "pu", '浦': "pu", '濮': "pu",
+	'莆': "pu", '蒲': "pu", '谱': "pu", '七': "qi", '其': "qi", '启': "qi", '奇': "qi", '岐': "qi", '戚': "qi", '旗': "qi",
+	'杞': "qi", '栖': "qi", '淇': "qi", '祁': "qi", '綦': "qi", '耆': "qi", '蕲': "qi", '起': "qi", '麒': "qi", '齐': "qi",
+	'恰': "qia", '乾': "qian", '前': "qian", '千': "qian", '潜': "qian", '谦': "qian", '迁': "qian", '铅': "qian", '阡': "qian", '黔': "qian",
+	'强': "qiang", '羌': "qiang", '巧': "qiao", '桥': "qiao", '硚': "qiao", '谯': "qiao", '且': "qie", '勤': "qin", '沁': "qin", '秦': "qin",
+	'钦': "qin", '庆': "qing", '晴': "qing", '清': "qing", '青': "qing", '琼': "qiong", '邛': "qiong", '丘': "qiu", '邱': "qiu", '区': "qu",
+	'曲': "qu", '朐': "qu", '渠': "qu", '衢': "qu", '全': "quan", '劝': "quan", '圈': "quan", '权': "quan", '泉': "quan", '确': "que",
+	'群': "qun", '壤': "rang", '让': "rang", '饶': "rao", '仁': "ren", '任': "ren", '日': "ri", '容': "rong", '榕': "rong", '荣': "rong",
+	'蓉': "rong", '融': "rong", '柔': "rou", '乳': "ru", '如': "ru", '汝': "ru", '瑞': "rui", '芮': "rui", '润': "run", '若': "ruo",
+	'撒': "sa", '萨': "sa", '塞': "sai", '赛': "sai", '三': "san", '桑': "sang", '色': "se", '厦': "sha", '沙': "sha", '莎': "sha",
+	'善': "shan", '山': "shan", '汕': "shan", '鄯': "shan", '陕': "shan", '上': "shang", '商': "shang", '尚': "shang", '绍': "shao", '邵': "shao",
+	'韶': "shao", '射': "she", '歙': "she", '涉': "she", '畲': "she", '社': "she", '审': "shen", '沈': "shen", '深': "shen", '申': "shen",
+	'神': "shen", '莘': "shen", '圣': "sheng", '嵊': "sheng", '省': "sheng", '胜': "sheng", '什': "shi", '十': "shi", '始': "shi", '市': "shi",
+	'师': "shi", '施': "shi", '氏': "shi", '浉': "shi", '狮': "shi", '石': "shi", '寿': "shou", '手': "shou", '首': "shou", '墅': "shu",
+	'曙': "shu", '树': "shu", '沭': "shu", '熟': "shu", '疏': "shu", '舒': "shu", '蜀': "shu", '双': "shuang", '水': "shui", '顺': "shun",
+	'朔': "shuo", '硕': "shuo", '四': "si", '寺': "si", '思': "si", '斯': "si", '泗': "si", '嵩': "song", '松': "song", '淞': "song",
+	'僳': "su", '宿': "su", '肃': "su", '苏': "su", '濉': "sui", '睢': "sui", '穗': "sui", '绥': "sui", '遂': "sui", '随': "sui",
+	'孙': "sun", '索': "suo", '塔': "ta", '台': "tai", '太': "tai", '泰': "tai", '坛': "tan", '滩': "tan", '潭': "tan", '覃': "tan",
+	'郯': "tan", '唐': "tang", '堂': "tang", '塘': "tang", '汤': "tang", '桃': "tao", '洮': "tao", '陶': "tao", '特': "te", '滕': "teng",
+	'腾': "teng", '藤': "teng", '提': "ti", '天': "tian", '田': "tian", '铁': "tie", '亭': "ting", '汀': "ting", '同': "tong", '桐': "tong",
+	'潼': "tong", '通': "tong", '铜': "tong", '头': "tou", '吐': "tu", '图': "tu", '土': "tu", '徒': "tu", '涂': "tu", '突': "tu",
+	'团': "tuan", '屯': "tun", '托': "tuo", '拖': "tuo", '脱': "tuo", '陀': "tuo", '佤': "wa", '洼': "wa", '瓦': "wa", '外': "wai",
+	'万': "wan", '宛': "wan", '湾': "wan", '旺': "wang", '望': "wang", '汪': "wang", '王': "wang", '为': "wei", '伟': "wei", '卫': "wei",
+	'围': "wei", '圩': "wei", '威': "wei", '尉': "wei", '尾': "wei", '巍': "wei", '微': "wei", '未': "wei", '渭': "wei", '潍': "wei",
+	'维': "wei", '蔚': "wei", '魏': "wei", '文': "wen", '汶': "wen", '温': "wen", '闻': "wen", '瓮': "weng", '翁': "weng", '卧': "wo",
+	'斡': "wo", '沃': "wo", '涡': "wo", '乌': "wu", '五': "wu", '伍': "wu", '务': "wu", '吴': "wu", '吾': "wu", '婺': "wu",
+	'巫': "wu", '悟': "wu", '无': "wu", '梧': "wu", '武': "wu", '舞': "wu", '芜': "wu", '习': "xi", '喜': "xi", '息': "xi",
+	'昔': "xi", '浠': "xi", '淅': "xi", '溪': "xi", '细': "xi", '西': "xi", '锡': "xi", '隰': "xi", '下': "xia", '夏': "xia",
+	'峡': "xia", '辖': "xia", '霞': "xia", '仙': "xian", '县': "xian", '咸': "xian", '献': "xian", '贤': "xian", '鲜': "xian", '乡': "xiang",
+	'厢': "xiang", '向': "xiang", '响': "xiang", '湘': "xiang", '相': "xiang", '祥': "xiang", '翔': "xiang", '芗': "xiang", '襄': "xiang", '象': "xiang",
+	'镶': "xiang", '项': "xiang", '香': "xiang", '孝': "xiao", '小': "xiao", '猇': "xiao", '萧': "xiao", '霄': "xiao", '谢': "xie", '信': "xin",
+	'心': "xin", '忻': "xin", '新': "xin", '辛': "xin", '兴': "xing", '星': "xing", '杏': "xing", '荥': "xing", '行': "xing", '邢': "xing",
+	'陉': "xing", '雄': "xiong", '休': "xiu", '修': "xiu", '岫': "xiu", '秀': "xiu", '叙': "xu", '徐': "xu", '溆': "xu", '盱': "xu",
+	'许': "xu", '宣': "xuan", '玄': "xuan", '穴': "xue", '薛': "xue", '寻': "xun", '循': "xun", '旬': "xun", '浔': "xun", '逊': "xun",
+	'亚': "ya", '牙': "ya", '琊': "ya", '雅': "ya", '鸭': "ya", '偃': "yan", '兖': "yan", '堰': "yan", '岩': "yan", '延': "yan",
+	'彦': "yan", '晏': "yan", '沿': "yan", '炎': "yan", '烟': "yan", '焉': "yan", '盐': "yan", '研': "yan", '砚': "yan", '郾': "yan",
+	'鄢': "yan", '阎': "yan", '雁': "yan", '央': "yang", '扬': "yang", '杨': "yang", '洋': "yang", '漾': "yang", '羊': "yang", '阳': "yang",
+	'姚': "yao", '尧': "yao", '瑶': "yao", '耀': "yao", '要': "yao", '遥': "yao", '业': "ye", '冶': "ye", '叶': "ye", '掖': "ye",
+	'邺': "ye", '野': "ye", '义': "yi", '仪': "yi", '伊': "yi", '依': "yi", '夷': "yi", '宜': "yi", '峄': "yi", '弋': "yi",
+	'彝': "yi", '易': "yi", '沂': "yi", '猗': "yi", '益': "yi", '眙': "yi", '翼': "yi", '谊': "yi", '邑': "yi", '驿': "yi",
+	'黟': "yi", '印': "yin", '殷': "yin", '荫': "yin", '鄞': "yin", '银': "yin", '阴': "yin", '音': "yin", '应': "ying", '盈': "ying",
+	'英': "ying", '营': "ying", '蓥': "ying", '迎': "ying", '颍': "ying", '鹰': "ying", '埇': "yong", '永': "yong", '邕': "yong", '雍': "yong",
+	'友': "you", '右': "you", '尤': "you", '攸': "you", '油': "you", '游': "you", '犹': "you", '邮': "you", '酉': "you", '于': "yu",
+	'余': "yu", '域': "yu", '宇': "yu", '屿': "yu", '峪': "yu", '榆': "yu", '渝': "yu", '玉': "yu", '盂': "yu", '禹': "yu",
+	'禺': "yu", '舆': "yu", '虞': "yu", '裕': "yu", '豫': "yu", '郁': "yu", '隅': "yu", '雨': "yu", '鱼': "yu", '元': "yuan",
+	'原': "yuan", '园': "yuan", '垣': "yuan", '沅': "yuan", '源': "yuan", '苑': "yuan", '袁': "yuan", '远': "yuan", '岳': "yue", '月': "yue",
+	'越': "yue", '云': "yun", '匀': "yun", '筠': "yun", '蕴': "yun", '运': "yun", '郓': "yun", '郧': "yun", '杂': "za", '载': "zai",
+	'赞': "zan", '藏': "zang", '枣': "zao", '则': "ze", '泽': "ze", '增': "zeng", '曾': "zeng", '扎': "zha", '札': "zha", '柞': "zha",
+	'闸': "zha", '寨': "zhai", '沾': "zhan", '湛': "zhan", '站': "zhan", '丈': "zhang", '张': "zhang", '彰': "zhang", '樟': "zhang", '漳': "zhang",
+	'章': "zhang", '召': "zhao", '招': "zhao", '昭': "zhao", '照': "zhao", '肇': "zhao", '诏': "zhao", '赵': "zhao", '柘': "zhe", '浙': "zhe",
+	'圳': "zhen", '振': "zhen", '浈': "zhen", '真': "zhen", '贞': "zhen", '镇': "zhen", '征': "zheng", '政': "zheng", '正': "zheng", '蒸': "zheng",
+	'郑': "zheng", '峙': "zhi", '志': "zhi", '指': "zhi", '枝': "zhi", '植': "zhi", '治': "zhi", '直': "zhi", '织': "zhi", '脂': "zhi",
+	'至': "zhi", '芝': "zhi", '芷': "zhi", '陟': "zhi", '中': "zhong", '仲': "zhong", '忠': "zhong", '重': "zhong", '钟': "zhong", '周': "zhou",
+	'州': "zhou", '洲': "zhou", '舟': "zhou", '主': "zhu", '助': "zhu", '柱': "zhu", '株': "zhu", '珠': "zhu", '祝': "zhu", '竹': "zhu",
+	'诸': "zhu", '驻': "zhu", '壮': "zhuang", '庄': "zhuang", '准': "zhun", '卓': "zhuo", '涿': "zhuo", '子': "zi", '孜': "zi", '梓': "zi",
+	'淄': "zi", '滋': "zi", '秭': "zi", '紫': "zi", '自': "zi", '资': "zi", '宗': "zong", '邹': "zou", '族': "zu", '足': "zu",
+	'嘴': "zui", '遵': "zun", '作': "zuo", '左': "zuo",
+}
@@ -0,0 +1,102 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description:
+
+package test
+
+import (
+	"errors"
+	"github.com/xfali/carea"
+	"github.com/xfali/carea/address"
+	"math"
+	"testing"
+)
+
+func TestAddressParse(t *testing.T) {
+	p, err := address.NewParser(carea.NewAreaService())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		addr   string
+		code   carea.AreaCode
+		detail string
+	}{
+		{"四川成都武侯区人民南路4段1号", "510107", "人民南路4段1号"},
+		{"四川省成都市武侯区人民南路4段1号", "510107", "人民南路4段1号"},
+		{"北京朝阳望京SOHO", "110105", "望京SOHO"},
+		{"中国北京市朝阳区望京SOHO", "110105", "望京SOHO"},
+		{"四川武侯区人民南路", "510107", "人民南路"},
+		{"吉林省吉林市船营区", "220204", ""},
+		{"广西南宁青秀区", "450103", ""},
+		{"成都市高新区天府大道", "510100", "高新区天府大道"},
+	}
+	for _, c := range cases {
+		r, err := p.Parse(c.addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Area().Code != c.code || r.Detail != c.detail {
+			t.Fatalf("%s: expect %s %s, got %s", c.addr, c.code, c.detail, r)
+		}
+		t.Log(r)
+	}
+
+	t.Run("confidence", func(t *testing.T) {
+		full, _ := p.Parse("四川省成都市武侯区")
+		short, _ := p.Parse("四川成都武侯区")
+		missing, _ := p.Parse("武侯区")
+		if !(full.Confidence > short.Confidence && short.Confidence > missing.Confidence) {
+			t.Fatal("unexpected confidence ", full, short, missing)
+		}
+		if full.Province.Code != "510000" || full.City.Code != "510100" {
+			t.Fatal("unexpected result ", full)
+		}
+	})
+
+	t.Run("placeholder", func(t *testing.T) {
+		// 直辖市的市辖区等占位层级不计入推断层级
+		cases := []struct {
+			addr       string
+			confidence float64
+		}{
+			{"上海市浦东新区世纪大道", 1},
+			{"上海浦东新区世纪大道", 1 - 0.05},
+			{"北京朝阳望京SOHO", 1 - 0.05*2},
+		}
+		for _, c := range cases {
+			r, err := p.Parse(c.addr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(r.Confidence-c.confidence) > 1e-9 {
+				t.Fatalf("%s: expect confidence %.2f, got %s", c.addr, c.confidence, r)
+			}
+		}
+	})
+
+	t.Run("ambiguous", func(t *testing.T) {
+		r, err := p.Parse("朝阳区建国路")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Confidence > 0.5 {
+			t.Fatal("expect low confidence, got ", r)
+		}
+		r, err = p.Parse("长春朝阳区建国路")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Area().Code != "220104" || r.Confidence < 0.5 {
+			t.Fatal("expect 220104, got ", r)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := p.Parse("火星基地1号")
+		if !errors.Is(err, carea.ErrAreaNotFound) {
+			t.Fatal("expect ErrAreaNotFound, got ", err)
+		}
+	})
+}
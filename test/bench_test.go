@@ -0,0 +1,55 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description:
+
+package test
+
+import (
+	"github.com/xfali/carea"
+	"testing"
+)
+
+func BenchmarkAreaByCode(b *testing.B) {
+	s := carea.NewAreaService()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := s.AreaByCode("510107", false)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSubareaByCode(b *testing.B) {
+	s := carea.NewAreaService()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := s.SubareaByCode("510000", true)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParentAreaByCode(b *testing.B) {
+	s := carea.NewAreaService()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := s.ParentAreaByCode("510107", true)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAreasWithSub(b *testing.B) {
+	s := carea.NewAreaService()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := s.Areas(true)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
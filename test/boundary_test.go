@@ -0,0 +1,211 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description:
+
+package test
+
+import (
+	"errors"
+	"github.com/xfali/carea"
+	"io/ioutil"
+	"math"
+	"os"
+	"strings"
+	"testing"
+)
+
+const boundaryGeoJSON = `{
+  "type": "FeatureCollection",
+  "features": [
+    {"type": "Feature", "properties": {"adcode": 510000, "name": "四川省"},
+     "geometry": {"type": "Polygon", "coordinates": [[[97, 26], [108, 26], [108, 34], [97, 34], [97, 26]]]}},
+    {"type": "Feature", "properties": {"code": "510100", "name": "成都市"},
+     "geometry": {"type": "Polygon", "coordinates": [
+       [[103, 30], [105, 30], [105, 31.5], [103, 31.5], [103, 30]],
+       [[104.5, 31], [104.8, 31], [104.8, 31.3], [104.5, 31.3], [104.5, 31]]]}},
+    {"type": "Feature", "id": "510104", "properties": {"name": "锦江区"},
+     "geometry": {"type": "MultiPolygon", "coordinates": [
+       [[[104, 30], [105, 30], [105, 31], [104, 31], [104, 30]]],
+       [[[110, 20], [110.1, 20], [110.1, 20.1], [110, 20]]]]}},
+    {"type": "Feature", "properties": {"code": "999999"},
+     "geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 0]]]}},
+    {"type": "Feature", "properties": {"name": "no code"},
+     "geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 0]]]}}
+  ]
+}`
+
+// 两个相邻的正方形共用一条arc，坐标经过量化与差分编码
+const boundaryTopoJSON = `{
+  "type": "Topology",
+  "transform": {"scale": [0.5, 0.5], "translate": [103, 30]},
+  "arcs": [
+    [[2, 0], [0, 2]],
+    [[2, 2], [-2, 0], [0, -2], [2, 0]],
+    [[2, 0], [2, 0], [0, 2], [-2, 0]]
+  ],
+  "objects": {
+    "areas": {"type": "GeometryCollection", "geometries": [
+      {"type": "Polygon", "arcs": [[0, 1]], "properties": {"code": "510105"}},
+      {"type": "Polygon", "arcs": [[2, -1]], "properties": {"code": "510104"}}
+    ]}
+  }
+}`
+
+func boundaryTestData() []carea.AreaData {
+	return []carea.AreaData{
+		{Code: "510000", ParentCode: "0", Level: "1", Name: "四川省"},
+		{Code: "510100", ParentCode: "510000", Level: "2", Name: "成都市"},
+		{Code: "510104", ParentCode: "510100", Level: "3", Name: "锦江区"},
+		{Code: "510105", ParentCode: "510100", Level: "3", Name: "青羊区"},
+	}
+}
+
+func newBoundaryService(t *testing.T, boundary string, opts ...carea.Opt) carea.AreaService {
+	f, err := ioutil.TempFile("", "boundary*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(boundary); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	s, err := carea.NewAreaServiceE(append([]carea.Opt{
+		carea.DefaultOpt.SetDataSource(func() ([]carea.AreaData, error) {
+			return boundaryTestData(), nil
+		}),
+		carea.DefaultOpt.LoadBoundaryFromFile(f.Name())}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestLoadBoundaries(t *testing.T) {
+	bs, err := carea.LoadBoundaries(strings.NewReader(boundaryGeoJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bs) != 4 {
+		t.Fatal("expect 4 boundaries but get ", len(bs))
+	}
+	b := bs["510104"]
+	if b == nil || len(b.Polygons) != 2 {
+		t.Fatal("unexpected boundary ", b)
+	}
+	if b.MinLatitude != 20 || b.MaxLatitude != 31 || b.MinLongitude != 104 || b.MaxLongitude != 110.1 {
+		t.Fatal("unexpected bounds ", b.MinLatitude, b.MaxLatitude, b.MinLongitude, b.MaxLongitude)
+	}
+
+	_, err = carea.LoadBoundaries(strings.NewReader(`{"type": "Point"}`))
+	if !errors.Is(err, carea.ErrInvalidData) {
+		t.Fatal("expect ErrInvalidData but get ", err)
+	}
+	_, err = carea.LoadBoundaries(strings.NewReader(`{"type": "Topology", "arcs": [],
+		"objects": {"a": {"type": "Polygon", "arcs": [[0]], "properties": {"code": "510104"}}}}`))
+	if !errors.Is(err, carea.ErrInvalidData) {
+		t.Fatal("expect ErrInvalidData but get ", err)
+	}
+}
+
+func TestLocate(t *testing.T) {
+	s := newBoundaryService(t, boundaryGeoJSON)
+	ret, err := s.Locate(30.5, 104.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []carea.AreaCode{"510000", "510100", "510104"}
+	if len(ret) != len(expect) {
+		t.Fatal("unexpected result ", ret)
+	}
+	for i, a := range ret {
+		if a.Code != expect[i] {
+			t.Fatalf("index %d expect %s but get %s", i, expect[i], a.Code)
+		}
+	}
+
+	// 成都市边界中的空洞
+	ret, err = s.Locate(31.1, 104.6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ret) != 1 || ret[0].Code != "510000" {
+		t.Fatal("unexpected result ", ret)
+	}
+
+	_, err = s.Locate(39.9, 116.4)
+	if !errors.Is(err, carea.ErrAreaNotFound) {
+		t.Fatal("expect ErrAreaNotFound but get ", err)
+	}
+}
+
+func TestLocateTopoJSON(t *testing.T) {
+	s := newBoundaryService(t, boundaryTopoJSON)
+	ret, err := s.Locate(30.5, 103.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ret) != 1 || ret[0].Code != "510105" {
+		t.Fatal("unexpected result ", ret)
+	}
+	ret, err = s.Locate(30.5, 104.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ret) != 1 || ret[0].Code != "510104" {
+		t.Fatal("unexpected result ", ret)
+	}
+}
+
+func TestContains(t *testing.T) {
+	s := newBoundaryService(t, boundaryGeoJSON)
+	ok, err := s.Contains("510104", 20.02, 110.05)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expect 510104 contains point in second polygon")
+	}
+	ok, err = s.Contains("510100", 31.1, 104.6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expect point in hole not contained")
+	}
+
+	_, err = s.Contains("510105", 30.5, 104.5)
+	if !errors.Is(err, carea.ErrBoundaryNotFound) {
+		t.Fatal("expect ErrBoundaryNotFound but get ", err)
+	}
+	_, err = s.Contains("999999", 30.5, 104.5)
+	if !errors.Is(err, carea.ErrAreaNotFound) {
+		t.Fatal("expect ErrAreaNotFound but get ", err)
+	}
+}
+
+func TestPolygonArea(t *testing.T) {
+	s := newBoundaryService(t, boundaryTopoJSON)
+	area, err := s.PolygonArea("510104")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 1°×1°，中心纬度30.5°
+	expect := 111.195 * 111.195 * math.Cos(30.5*math.Pi/180)
+	if math.Abs(area-expect)/expect > 0.01 {
+		t.Fatalf("expect about %f but get %f", expect, area)
+	}
+
+	s = newBoundaryService(t, boundaryGeoJSON)
+	area, err = s.PolygonArea("510100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	outer := 2 * 1.5 * 111.195 * 111.195 * math.Cos(30.75*math.Pi/180)
+	hole := 0.3 * 0.3 * 111.195 * 111.195 * math.Cos(31.15*math.Pi/180)
+	if math.Abs(area-(outer-hole))/(outer-hole) > 0.01 {
+		t.Fatalf("expect about %f but get %f", outer-hole, area)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description:
+
+package test
+
+import (
+	"github.com/xfali/carea"
+	"testing"
+)
+
+func TestAreaCodeStructure(t *testing.T) {
+	cases := []struct {
+		code         carea.AreaCode
+		valid        bool
+		level        carea.AreaLevel
+		province     carea.AreaCode
+		prefecture   carea.AreaCode
+		parent       carea.AreaCode
+		municipality bool
+	}{
+		{"510000", true, "1", "510000", "", "0", false},
+		{"510100", true, "2", "510000", "510100", "510000", false},
+		{"510107", true, "3", "510000", "510100", "510100", false},
+		{"110105", true, "3", "110000", "110100", "110100", true},
+		{"500228", true, "3", "500000", "500200", "500200", true},
+		{"429004", true, "3", "420000", "429000", "429000", false},
+		{"110100", true, "2", "110000", "110100", "110000", false},
+		{"510007", false, "", "", "", "", false},
+		{"001000", false, "", "", "", "", false},
+		{"51010", false, "", "", "", "", false},
+		{"51010a", false, "", "", "", "", false},
+	}
+	for _, c := range cases {
+		if c.code.IsValidFormat() != c.valid {
+			t.Fatalf("%s: expect valid %v", c.code, c.valid)
+		}
+		if c.code.InferredLevel() != c.level {
+			t.Fatalf("%s: expect level %s, got %s", c.code, c.level, c.code.InferredLevel())
+		}
+		if c.code.ProvinceCode() != c.province {
+			t.Fatalf("%s: expect province %s, got %s", c.code, c.province, c.code.ProvinceCode())
+		}
+		if c.code.PrefectureCode() != c.prefecture {
+			t.Fatalf("%s: expect prefecture %s, got %s", c.code, c.prefecture, c.code.PrefectureCode())
+		}
+		if c.code.InferredParentCode() != c.parent {
+			t.Fatalf("%s: expect parent %s, got %s", c.code, c.parent, c.code.InferredParentCode())
+		}
+		if c.code.IsMunicipalityDistrict() != c.municipality {
+			t.Fatalf("%s: expect municipality district %v", c.code, c.municipality)
+		}
+	}
+}
+
+func TestAreaCodeDataset(t *testing.T) {
+	s := carea.NewAreaService()
+	v, err := s.Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range v {
+		if !a.Code.IsValidFormat() {
+			t.Fatal("invalid code ", a.Code)
+		}
+		if a.Code.InferredLevel() != a.Level {
+			t.Fatalf("%s: expect level %s, got %s", a.Code, a.Level, a.Code.InferredLevel())
+		}
+		if a.Code.InferredParentCode() != a.ParentCode {
+			t.Fatalf("%s: expect parent %s, got %s", a.Code, a.ParentCode, a.Code.InferredParentCode())
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description:
+
+package test
+
+import (
+	"github.com/xfali/carea"
+	"math"
+	"testing"
+)
+
+func TestLocation(t *testing.T) {
+	s := carea.NewAreaService()
+	a, err := s.AreaByCode("510000", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lat, lng, ok := a.Location()
+	if !ok || lat != 0.367481 || lng != 102.89916 {
+		t.Fatal("unexpected location ", lat, lng, ok)
+	}
+
+	a, err = s.AreaByCode("110100", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.HasLocation() {
+		t.Fatal("expect 110100 without location")
+	}
+
+	for _, c := range [][2]string{{"NaN", "116.4"}, {"39.9", "Inf"}, {"-Inf", "116.4"}, {"39.9", "nan"}} {
+		ad := carea.AreaData{Code: "110000", Latitude: c[0], Longitude: c[1]}
+		if ad.HasLocation() {
+			t.Fatal("expect no location for ", c)
+		}
+		fc, err := carea.ToGeoJSON([]carea.Area{{AreaData: ad}}, carea.DefaultGeoJSONOpt.KeepMissing())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fc.String() == "" || fc.Features[0].Geometry != nil {
+			t.Fatal("unexpected feature ", fc.Features)
+		}
+	}
+}
+
+func TestFillCentroid(t *testing.T) {
+	s, err := carea.NewAreaServiceE(carea.DefaultOpt.FillCentroid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := s.AreaByCode("110100", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lat, lng, ok := a.Location()
+	if !ok {
+		t.Fatal("expect 110100 with centroid")
+	}
+	// 截断纬度的西城区、丰台区等不参与计算
+	if math.Abs(lat-39.9) > 0.5 || math.Abs(lng-116.4) > 0.5 {
+		t.Fatal("expect 110100 near Beijing but get ", lat, lng)
+	}
+	t.Log(a.Coordinate())
+	for code, c := range map[carea.AreaCode][2]float64{
+		"120100": {39.1, 117.2},
+		"310100": {31.2, 121.5},
+	} {
+		m, err := s.AreaByCode(code, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lat, lng, ok := m.Location()
+		if !ok || math.Abs(lat-c[0]) > 0.5 || math.Abs(lng-c[1]) > 0.5 {
+			t.Fatal("unexpected centroid of ", code, lat, lng)
+		}
+	}
+
+	// 原始数据不受影响
+	d, err := s.Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range d {
+		if v.Code == "110100" && v.HasLocation() {
+			t.Fatal("data source should not be modified")
+		}
+	}
+
+	// 子区域均无坐标时保持缺失
+	a, err = s.AreaByCode("460323", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.HasLocation() {
+		t.Fatal("expect 460323 without location")
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description:
+
+package test
+
+import (
+	"errors"
+	"github.com/xfali/carea"
+	"math"
+	"testing"
+)
+
+func almostEqual(a, b, epsilon float64) bool {
+	return math.Abs(a-b) < epsilon
+}
+
+func TestCoordinateConversion(t *testing.T) {
+	lat, lng := carea.WGS84ToGCJ02(31.1774276, 121.5272106)
+	if !almostEqual(lat, 31.17530398364597, 1e-9) || !almostEqual(lng, 121.531541859215, 1e-9) {
+		t.Fatal("unexpected GCJ-02 coordinate ", lat, lng)
+	}
+	lat, lng = carea.GCJ02ToWGS84(lat, lng)
+	if !almostEqual(lat, 31.1774276, 1e-8) || !almostEqual(lng, 121.5272106, 1e-8) {
+		t.Fatal("unexpected WGS-84 coordinate ", lat, lng)
+	}
+
+	lat, lng = carea.GCJ02ToBD09(39.915, 116.404)
+	if !almostEqual(lat, 39.921337, 1e-6) || !almostEqual(lng, 116.410369, 1e-6) {
+		t.Fatal("unexpected BD-09 coordinate ", lat, lng)
+	}
+	lat, lng = carea.BD09ToGCJ02(lat, lng)
+	if !almostEqual(lat, 39.915, 1e-5) || !almostEqual(lng, 116.404, 1e-5) {
+		t.Fatal("unexpected GCJ-02 coordinate ", lat, lng)
+	}
+
+	lat, lng = carea.BD09ToWGS84(carea.WGS84ToBD09(30.66, 104.06))
+	if !almostEqual(lat, 30.66, 1e-5) || !almostEqual(lng, 104.06, 1e-5) {
+		t.Fatal("unexpected WGS-84 coordinate ", lat, lng)
+	}
+
+	// 中国境外不加偏
+	lat, lng = carea.WGS84ToGCJ02(48.8566, 2.3522)
+	if lat != 48.8566 || lng != 2.3522 {
+		t.Fatal("expect no offset outside China ", lat, lng)
+	}
+}
+
+func TestConvertCoordinate(t *testing.T) {
+	c := carea.Coordinate{Latitude: 30.66, Longitude: 104.06}
+	bd, err := c.Convert(carea.WGS84, carea.BD09)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lat, lng := carea.WGS84ToBD09(30.66, 104.06)
+	if bd.Latitude != lat || bd.Longitude != lng {
+		t.Fatal("unexpected coordinate ", bd)
+	}
+	same, err := c.Convert(carea.GCJ02, carea.GCJ02)
+	if err != nil || same != c {
+		t.Fatal("expect same coordinate ", same, err)
+	}
+	_, err = c.Convert(carea.WGS84, carea.CoordSystem("CGCS2000"))
+	if !errors.Is(err, carea.ErrInvalidCoordinate) {
+		t.Fatal("expect ErrInvalidCoordinate but get ", err)
+	}
+}
+
+func TestServiceCoordSystem(t *testing.T) {
+	if carea.BuildinCoordSystem != carea.BD09 {
+		t.Fatal("unexpected buildin coordinate system ", carea.BuildinCoordSystem)
+	}
+	ds := carea.DefaultOpt.SetDataSource(func() ([]carea.AreaData, error) {
+		return spatialTestData(), nil
+	})
+	s, err := carea.NewAreaServiceE(ds, carea.DefaultOpt.DataCoordSystem(carea.GCJ02), carea.DefaultOpt.CoordSystem(carea.WGS84))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.CoordSystem() != carea.WGS84 {
+		t.Fatal("unexpected coordinate system ", s.CoordSystem())
+	}
+	a, err := s.AreaByCode("510105", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lat, lng, _ := a.Location()
+	wLat, wLng := carea.GCJ02ToWGS84(30.667648, 104.055731)
+	if !almostEqual(lat, wLat, 1e-6) || !almostEqual(lng, wLng, 1e-6) {
+		t.Fatal("unexpected coordinate ", lat, lng)
+	}
+	// 原始数据不变
+	d, err := s.Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ad := range d {
+		if ad.Code == "510105" && ad.Latitude != "30.667648" {
+			t.Fatal("expect raw data unchanged ", ad.Latitude)
+		}
+	}
+
+	// 输入坐标系与服务坐标系不同
+	bLat, bLng := carea.GCJ02ToBD09(30.667648, 104.055731)
+	n, err := s.NearestArea(bLat, bLng, carea.CountyLevel, carea.DefaultSpatialOpt.CoordSystem(carea.BD09))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.Code != "510105" || n.Distance > 0.01 {
+		t.Fatal("unexpected result ", n.Code, n.Distance)
+	}
+	n, err = s.NearestArea(wLat, wLng, carea.CountyLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.Code != "510105" || n.Distance > 0.01 {
+		t.Fatal("unexpected result ", n.Code, n.Distance)
+	}
+
+	_, err = carea.NewAreaServiceE(ds, carea.DefaultOpt.DataCoordSystem(carea.CoordSystem("unknown")))
+	if !errors.Is(err, carea.ErrInvalidCoordinate) {
+		t.Fatal("expect ErrInvalidCoordinate but get ", err)
+	}
+}
+
+func TestBoundaryCoordSystem(t *testing.T) {
+	s := newBoundaryService(t, boundaryTopoJSON, carea.DefaultOpt.DataCoordSystem(carea.GCJ02))
+	// GCJ-02坐标(30.5, 104.995)位于锦江区东侧边界附近，转换为BD-09后输入
+	lat, lng := carea.GCJ02ToBD09(30.5, 104.995)
+	ok, err := s.Contains("510104", lat, lng, carea.DefaultSpatialOpt.CoordSystem(carea.BD09))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expect 510104 contains point")
+	}
+	// 不指定坐标系时按GCJ-02处理，BD-09坐标偏出边界
+	ok, err = s.Contains("510104", lat, lng)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expect BD-09 coordinate outside 510104")
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description:
+
+package test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/xfali/carea"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const areasCSV = "\ufeff编码,上级编码,Level,名称,lat,lng,人口,备注\n" +
+	"510000,0,1,四川省,30.651226,104.075881,8367,\n" +
+	"510100,510000,2,成都市,30.659462,104.065735,2119,\"省会, 副省级\"\n" +
+	"510104,510100,3,锦江区,,,,\n"
+
+func TestLoadCSVData(t *testing.T) {
+	data, layout, err := carea.LoadCSVData(strings.NewReader(areasCSV),
+		carea.DefaultCSVOpt.Column(carea.CSVCode, "编码"),
+		carea.DefaultCSVOpt.Column(carea.CSVParentCode, "上级编码"),
+		carea.DefaultCSVOpt.Column(carea.CSVName, "名称"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 3 {
+		t.Fatal("expect 3 records but get ", len(data))
+	}
+	ad := data[1]
+	if ad.Code != "510100" || ad.ParentCode != "510000" || ad.Level != "2" || ad.Name != "成都市" ||
+		ad.Latitude != "30.659462" || ad.Longitude != "104.065735" {
+		t.Fatal("unexpected record ", ad)
+	}
+	// 附加列不影响AreaData的可比较性
+	if ad != data[1] || ad == data[0] {
+		t.Fatal("unexpected comparison result")
+	}
+	expect := []string{carea.CSVCode, carea.CSVParentCode, carea.CSVLevel, carea.CSVName,
+		carea.CSVLatitude, carea.CSVLongitude, "人口", "备注"}
+	if !reflect.DeepEqual(layout.Columns, expect) || layout.Header[2] != "Level" || layout.Comma != ',' {
+		t.Fatal("unexpected layout ", layout)
+	}
+	if !reflect.DeepEqual(layout.Extra["510100"], map[string]string{"人口": "2119", "备注": "省会, 副省级"}) {
+		t.Fatal("unexpected extra ", layout.Extra["510100"])
+	}
+	if _, ok := layout.Extra["510104"]; ok {
+		t.Fatal("expect empty extra but get ", layout.Extra["510104"])
+	}
+}
+
+func TestLoadCSVDataWithoutHeader(t *testing.T) {
+	tsv := "四川省\t510000\t0\t1\n成都市\t510100\t510000\t2\n"
+	data, _, err := carea.LoadCSVData(strings.NewReader(tsv),
+		carea.DefaultCSVOpt.TSV(),
+		carea.DefaultCSVOpt.Index(carea.CSVName, 0),
+		carea.DefaultCSVOpt.Index(carea.CSVCode, 1),
+		carea.DefaultCSVOpt.Index(carea.CSVParentCode, 2),
+		carea.DefaultCSVOpt.Index(carea.CSVLevel, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 2 || data[1].Code != "510100" || data[1].Name != "成都市" || data[1].Level != "2" {
+		t.Fatal("unexpected records ", data)
+	}
+
+	// 默认列顺序
+	data, layout, err := carea.LoadCSVData(strings.NewReader("510000,0,1,四川省,,,x\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 1 || data[0].Name != "四川省" || layout.Extra["510000"]["column7"] != "x" || layout.Header != nil {
+		t.Fatal("unexpected records ", data)
+	}
+
+	_, _, err = carea.LoadCSVData(strings.NewReader("code,name\n,四川省\n"))
+	if !errors.Is(err, carea.ErrInvalidData) {
+		t.Fatal("expect ErrInvalidData but get ", err)
+	}
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	opts := []carea.CSVOpt{
+		carea.DefaultCSVOpt.Column(carea.CSVCode, "编码"),
+		carea.DefaultCSVOpt.Column(carea.CSVParentCode, "上级编码"),
+		carea.DefaultCSVOpt.Column(carea.CSVName, "名称"),
+	}
+	data, layout, err := carea.LoadCSVData(strings.NewReader(areasCSV), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := bytes.Buffer{}
+	if err := carea.WriteCSV(&buf, data, carea.DefaultCSVOpt.Layout(layout)); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != strings.TrimPrefix(areasCSV, "\ufeff") {
+		t.Fatalf("unexpected output %q", buf.String())
+	}
+
+	// 未指定列布局时按默认列顺序写出
+	buf.Reset()
+	if err := carea.WriteCSV(&buf, data, opts...); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "编码,上级编码,level,名称,latitude,longitude\n") {
+		t.Fatal("unexpected header ", buf.String())
+	}
+	ret, _, err := carea.LoadCSVData(&buf, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(data, ret) {
+		t.Fatal("round trip mismatch ", ret)
+	}
+
+	// 无表头、TSV、自定义列序号
+	opts = []carea.CSVOpt{
+		carea.DefaultCSVOpt.TSV(),
+		carea.DefaultCSVOpt.Header(false),
+		carea.DefaultCSVOpt.Index(carea.CSVName, 0),
+		carea.DefaultCSVOpt.Index(carea.CSVCode, 2),
+		carea.DefaultCSVOpt.Index(carea.CSVParentCode, 3),
+		carea.DefaultCSVOpt.Index(carea.CSVLevel, 4),
+	}
+	tsv := "四川省\t川\t510000\t0\t1\t\t\n成都市\t\t510100\t510000\t2\t\t蓉\n"
+	data, layout, err = carea.LoadCSVData(strings.NewReader(tsv), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if layout.Extra["510000"]["column2"] != "川" || layout.Extra["510100"]["column7"] != "蓉" {
+		t.Fatal("unexpected extra ", layout.Extra)
+	}
+	buf.Reset()
+	if err := carea.WriteCSV(&buf, data, carea.DefaultCSVOpt.Layout(layout)); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != tsv {
+		t.Fatalf("unexpected output %q", buf.String())
+	}
+}
+
+// 表头中区域字段的顺序与默认顺序不同，且没有经纬度列
+const customCSV = "区划代码,名称,人口,上级,级别\n" +
+	"510000,四川省,8367,0,1\n" +
+	"510100,成都市,2119,510000,2\n"
+
+func TestExportCSVFromFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "areas*.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(customCSV); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	s, err := carea.NewAreaServiceE(carea.DefaultOpt.LoadFromCSVFile(f.Name(),
+		carea.DefaultCSVOpt.Column(carea.CSVCode, "区划代码"),
+		carea.DefaultCSVOpt.Column(carea.CSVName, "名称"),
+		carea.DefaultCSVOpt.Column(carea.CSVParentCode, "上级"),
+		carea.DefaultCSVOpt.Column(carea.CSVLevel, "级别")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := bytes.Buffer{}
+	if err := carea.ExportCSV(&buf, s); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != customCSV {
+		t.Fatalf("unexpected output %q", buf.String())
+	}
+}
+
+func TestExportCSVBuildin(t *testing.T) {
+	s := carea.NewAreaService()
+	buf := bytes.Buffer{}
+	if err := carea.ExportCSV(&buf, s); err != nil {
+		t.Fatal(err)
+	}
+	f, err := ioutil.TempFile("", "areas*.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	s2, err := carea.NewAreaServiceE(carea.DefaultOpt.LoadFromCSVFile(f.Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d1, err := s.Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d2, err := s2.Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(d1, d2) {
+		t.Fatal("round trip mismatch")
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description:
+
+package test
+
+import (
+	"encoding/json"
+	"github.com/xfali/carea"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	oldService := newDataService(t, []carea.AreaData{
+		{Code: "110000", ParentCode: "0", Level: "1", Name: "北京市", Latitude: "39.9", Longitude: "116.4"},
+		{Code: "110200", ParentCode: "110000", Level: "2", Name: "县"},
+		{Code: "110228", ParentCode: "110200", Level: "3", Name: "密云县", Latitude: "40.53", Longitude: "117.0"},
+		{Code: "120000", ParentCode: "0", Level: "1", Name: "天津市"},
+		{Code: "120100", ParentCode: "120000", Level: "2", Name: "市辖区"},
+		{Code: "120101", ParentCode: "120100", Level: "3", Name: "和平区"},
+	})
+	newService := newDataService(t, []carea.AreaData{
+		{Code: "110000", ParentCode: "0", Level: "1", Name: "北京市", Latitude: "39.91", Longitude: "116.4"},
+		{Code: "110200", ParentCode: "110000", Level: "2", Name: "县"},
+		{Code: "110118", ParentCode: "110200", Level: "3", Name: "密云区"},
+		{Code: "120000", ParentCode: "0", Level: "1", Name: "天津市", Latitude: "39.12", Longitude: "117.2"},
+		{Code: "120100", ParentCode: "120000", Level: "2", Name: "天津市辖区"},
+		{Code: "120101", ParentCode: "120000", Level: "2", Name: "和平区"},
+	})
+	report, err := carea.Diff(oldService, newService)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(report)
+	if report.OldTotal != 6 || report.NewTotal != 6 || report.IsEmpty() {
+		t.Fatal("unexpected report ", report)
+	}
+	expect := []struct {
+		t    carea.DiffType
+		code carea.AreaCode
+	}{
+		{carea.DiffCoordinateChanged, "110000"},
+		{carea.DiffAdded, "110118"},
+		{carea.DiffRemoved, "110228"},
+		{carea.DiffCoordinateChanged, "120000"},
+		{carea.DiffRenamed, "120100"},
+		{carea.DiffReparented, "120101"},
+		{carea.DiffReleveled, "120101"},
+	}
+	if len(report.Entries) != len(expect) {
+		t.Fatal("unexpected entries ", report.Entries)
+	}
+	for i, e := range report.Entries {
+		if e.Type != expect[i].t || e.Code != expect[i].code {
+			t.Fatalf("index %d expect %s %s but get %s %s", i, expect[i].t, expect[i].code, e.Type, e.Code)
+		}
+	}
+	if e := report.Entries[0]; e.Distance < 1.1 || e.Distance > 1.2 {
+		t.Fatal("unexpected distance ", e.Distance)
+	}
+	if e := report.Entries[3]; e.Distance != 0 || e.Old.HasLocation() || !e.New.HasLocation() {
+		t.Fatal("unexpected entry ", e)
+	}
+	if len(report.ByType(carea.DiffCoordinateChanged)) != 2 {
+		t.Fatal("expect 2 coordinate changes")
+	}
+
+	b, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded carea.DiffReport
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Entries) != len(expect) || decoded.Entries[1].Old != nil || decoded.Entries[1].New.Name != "密云区" {
+		t.Fatal("unexpected decoded report ", decoded)
+	}
+
+	report, err = carea.Diff(oldService, oldService)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.IsEmpty() {
+		t.Fatal("expect empty report but get ", report)
+	}
+}
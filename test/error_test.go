@@ -0,0 +1,75 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description:
+
+package test
+
+import (
+	"errors"
+	"github.com/xfali/carea"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestAreaErrors(t *testing.T) {
+	s := carea.NewAreaService()
+	t.Run("not found", func(t *testing.T) {
+		_, err := s.AreaByCode("999999", false)
+		if !errors.Is(err, carea.ErrAreaNotFound) {
+			t.Fatal("expect ErrAreaNotFound, got ", err)
+		}
+		var ae *carea.AreaError
+		if !errors.As(err, &ae) || ae.Code != "999999" {
+			t.Fatal("expect AreaError with code, got ", err)
+		}
+		t.Log(err)
+	})
+
+	t.Run("level", func(t *testing.T) {
+		_, err := s.AreaByLevel("9", false)
+		if !errors.Is(err, carea.ErrLevelOutOfRange) {
+			t.Fatal("expect ErrLevelOutOfRange, got ", err)
+		}
+		t.Log(err)
+	})
+}
+
+func TestNewAreaServiceE(t *testing.T) {
+	t.Run("missing file", func(t *testing.T) {
+		_, err := carea.NewAreaServiceFromFileE("not_exist.json")
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Fatal("expect os.ErrNotExist, got ", err)
+		}
+		if errors.Is(err, carea.ErrInvalidData) {
+			t.Fatal("missing file should not be ErrInvalidData")
+		}
+	})
+
+	t.Run("bad json", func(t *testing.T) {
+		f, err := ioutil.TempFile("", "bad*.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.Remove(f.Name())
+		_, err = f.WriteString("{bad")
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = carea.NewAreaServiceFromFileE(f.Name())
+		if !errors.Is(err, carea.ErrInvalidData) {
+			t.Fatal("expect ErrInvalidData, got ", err)
+		}
+		t.Log(err)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		s, err := carea.NewAreaServiceFromFileE("../static/data.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Log("level: ", s.AreaLevelNumber())
+	})
+}
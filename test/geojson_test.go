@@ -0,0 +1,126 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description:
+
+package test
+
+import (
+	"encoding/json"
+	"github.com/xfali/carea"
+	"testing"
+)
+
+// 第二个多边形的环未闭合
+const geoJSONBoundary = `{
+  "type": "FeatureCollection",
+  "features": [
+    {"type": "Feature", "id": "510104", "properties": {"name": "锦江区"},
+     "geometry": {"type": "MultiPolygon", "coordinates": [
+       [[[104, 30], [105, 30], [105, 31], [104, 31], [104, 30]]],
+       [[[110, 20], [110.1, 20], [110.1, 20.1], [110, 20.1]]]]}}
+  ]
+}`
+
+func TestToGeoJSON(t *testing.T) {
+	s := newDataService(t, spatialTestData())
+	a, err := s.AreaByCode("510100", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	areas := []carea.Area{a}
+	fc, err := carea.ToGeoJSON(areas)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fc.Type != "FeatureCollection" {
+		t.Fatal("unexpected type ", fc.Type)
+	}
+	// 青白江区缺失坐标被跳过
+	if len(fc.Features) != 5 {
+		t.Fatal("expect 5 features but get ", len(fc.Features))
+	}
+	for _, f := range fc.Features {
+		if f.Geometry == nil || f.Geometry.Type != carea.GeometryPoint {
+			t.Fatal("expect point feature but get ", f.Geometry)
+		}
+		if f.Properties["code"] == carea.AreaCode("510113") {
+			t.Fatal("expect 510113 skipped")
+		}
+	}
+	f := fc.Features[0]
+	if f.Properties["code"] != carea.AreaCode("510100") || f.Properties["name"] != "成都市" ||
+		f.Properties["level"] != carea.AreaLevel("2") || f.Properties["parentCode"] != carea.AreaCode("510000") {
+		t.Fatal("unexpected properties ", f.Properties)
+	}
+	coords := f.Geometry.Coordinates.([]float64)
+	if coords[0] != 104.065735 || coords[1] != 30.659462 {
+		t.Fatal("unexpected coordinates ", coords)
+	}
+
+	fc, err = carea.ToGeoJSON(areas, carea.DefaultGeoJSONOpt.KeepMissing())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fc.Features) != 6 {
+		t.Fatal("expect 6 features but get ", len(fc.Features))
+	}
+	for _, f := range fc.Features {
+		if f.Properties["code"] == carea.AreaCode("510113") {
+			if f.Geometry != nil || f.Properties["missingCoordinate"] != true {
+				t.Fatal("expect missing coordinate flagged ", f)
+			}
+		}
+	}
+
+	fc, err = carea.ToGeoJSON(areas, carea.DefaultGeoJSONOpt.Recursion(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fc.Features) != 1 {
+		t.Fatal("expect 1 feature but get ", len(fc.Features))
+	}
+}
+
+func TestToGeoJSONBoundary(t *testing.T) {
+	s := newBoundaryService(t, geoJSONBoundary)
+	areas, err := s.AreaByLevel(carea.CountyLevel, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fc, err := carea.ToGeoJSON(areas, carea.DefaultGeoJSONOpt.WithBoundary(s), carea.DefaultGeoJSONOpt.KeepMissing())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fc.Features) != 2 {
+		t.Fatal("expect 2 features but get ", len(fc.Features))
+	}
+	for _, f := range fc.Features {
+		switch f.Properties["code"] {
+		case carea.AreaCode("510104"):
+			if f.Geometry == nil || f.Geometry.Type != carea.GeometryMultiPolygon {
+				t.Fatal("expect MultiPolygon but get ", f.Geometry)
+			}
+		case carea.AreaCode("510105"):
+			if f.Geometry != nil {
+				t.Fatal("expect null geometry but get ", f.Geometry)
+			}
+		}
+	}
+
+	// 未闭合的环在导出时闭合
+	var doc struct {
+		Features []struct {
+			Geometry struct {
+				Coordinates [][][][]float64 `json:"coordinates"`
+			} `json:"geometry"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal([]byte(fc.String()), &doc); err != nil {
+		t.Fatal(err)
+	}
+	ring := doc.Features[0].Geometry.Coordinates[1][0]
+	if len(ring) != 5 || ring[0][0] != ring[4][0] || ring[0][1] != ring[4][1] {
+		t.Fatal("expect closed ring but get ", ring)
+	}
+}
@@ -0,0 +1,247 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description:
+
+package test
+
+import (
+	"errors"
+	"github.com/xfali/carea"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func historyTestHistory() *carea.History {
+	v2013 := []carea.AreaData{
+		{Code: "110000", ParentCode: "0", Level: "1", Name: "北京市"},
+		{Code: "110200", ParentCode: "110000", Level: "2", Name: "县"},
+		{Code: "110228", ParentCode: "110200", Level: "3", Name: "密云县"},
+	}
+	v2016 := []carea.AreaData{
+		{Code: "110000", ParentCode: "0", Level: "1", Name: "北京市"},
+		{Code: "110100", ParentCode: "110000", Level: "2", Name: "市辖区"},
+		{Code: "110118", ParentCode: "110100", Level: "3", Name: "密云区"},
+	}
+	return carea.NewHistory(carea.BuildinChangeLog()...).
+		AddVersion("2013", func() ([]carea.AreaData, error) { return v2013, nil }).
+		AddVersion("2016", func() ([]carea.AreaData, error) { return v2016, nil })
+}
+
+func TestChangeLogResolve(t *testing.T) {
+	l := carea.ChangeLog{
+		{Version: "2010", Type: carea.ChangeRename, From: "100001", To: []carea.AreaCode{"100002"}},
+		{Version: "2012", Type: carea.ChangeSplit, From: "100002", To: []carea.AreaCode{"100003", "100004"}},
+		{Version: "2014", Type: carea.ChangeMerge, From: "100004", To: []carea.AreaCode{"100005"}},
+		{Version: "2014", Type: carea.ChangeMerge, From: "100003", To: []carea.AreaCode{"100005"}},
+		{Version: "2011", Type: carea.ChangeAbolish, From: "100009"},
+		{Version: "2011", Type: carea.ChangeRename, From: "100007", To: []carea.AreaCode{"100008"}},
+		{Version: "2012", Type: carea.ChangeRename, From: "100008", To: []carea.AreaCode{"100007"}},
+	}
+	ret, err := l.Resolve("100001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ret, []carea.AreaCode{"100005"}) {
+		t.Fatal("unexpected result ", ret)
+	}
+	ret, err = l.Resolve("100002000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ret, []carea.AreaCode{"100005"}) {
+		t.Fatal("unexpected result ", ret)
+	}
+	ret, err = l.Resolve("100006")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ret, []carea.AreaCode{"100006"}) {
+		t.Fatal("unexpected result ", ret)
+	}
+	_, err = l.Resolve("100009")
+	if !errors.Is(err, carea.ErrCodeAbolished) {
+		t.Fatal("expect ErrCodeAbolished but get ", err)
+	}
+	// 更名后又改回，当前代码为改回后的代码
+	ret, err = l.Resolve("100007")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ret, []carea.AreaCode{"100007"}) {
+		t.Fatal("unexpected result ", ret)
+	}
+	ret, err = l.Resolve("100008")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ret, []carea.AreaCode{"100007"}) {
+		t.Fatal("unexpected result ", ret)
+	}
+	// 同一版本内成环的变更记录不会无限查找
+	ret, err = carea.ChangeLog{
+		{Version: "2012", Type: carea.ChangeRename, From: "100011", To: []carea.AreaCode{"100012"}},
+		{Version: "2012", Type: carea.ChangeRename, From: "100012", To: []carea.AreaCode{"100011"}},
+	}.Resolve("100011")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ret, []carea.AreaCode{"100011"}) {
+		t.Fatal("unexpected result ", ret)
+	}
+	// 更名后又改回，之后再次更名
+	ret, err = carea.ChangeLog{
+		{Version: "2011", Type: carea.ChangeRename, From: "100011", To: []carea.AreaCode{"100012"}},
+		{Version: "2012", Type: carea.ChangeRename, From: "100012", To: []carea.AreaCode{"100011"}},
+		{Version: "2015", Type: carea.ChangeRename, From: "100011", To: []carea.AreaCode{"100013"}},
+	}.Resolve("100011")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ret, []carea.AreaCode{"100013"}) {
+		t.Fatal("unexpected result ", ret)
+	}
+	// 代码被重新使用前的变更记录不适用
+	ret, err = carea.ChangeLog{
+		{Version: "2010", Type: carea.ChangeRename, From: "100002", To: []carea.AreaCode{"100003"}},
+		{Version: "2014", Type: carea.ChangeRename, From: "100001", To: []carea.AreaCode{"100002"}},
+	}.Resolve("100001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ret, []carea.AreaCode{"100002"}) {
+		t.Fatal("unexpected result ", ret)
+	}
+
+	l = carea.ChangeLog{
+		{Version: "2012", Type: carea.ChangeSplit, From: "100002", To: []carea.AreaCode{"100003", "100004"}},
+	}
+	ret, err = l.Resolve("100002")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ret, []carea.AreaCode{"100003", "100004"}) {
+		t.Fatal("unexpected result ", ret)
+	}
+}
+
+func TestLoadChangeLog(t *testing.T) {
+	l, err := carea.LoadChangeLog(strings.NewReader(`[
+		{"version": "2015", "type": "rename", "from": "110228", "to": ["110118"], "name": "密云县"},
+		{"version": "2016", "type": "abolish", "from": "110229"}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l) != 2 || l[0].Type != carea.ChangeRename || l[1].Type != carea.ChangeAbolish || len(l[1].To) != 0 {
+		t.Fatal("unexpected change log ", l)
+	}
+	_, err = carea.LoadChangeLog(strings.NewReader(`{`))
+	if !errors.Is(err, carea.ErrInvalidData) {
+		t.Fatal("expect ErrInvalidData but get ", err)
+	}
+}
+
+func TestBuildinResolveCode(t *testing.T) {
+	s := carea.NewAreaService()
+	if s.Version() != carea.BuildinVersion {
+		t.Fatal("unexpected version ", s.Version())
+	}
+	l := carea.BuildinChangeLog()
+	for old, cur := range map[carea.AreaCode]carea.AreaCode{
+		"110228": "110118",
+		"110229": "110119",
+		"120221": "120117",
+		"120225": "120119",
+		"110101": "110101",
+	} {
+		ret, err := l.Resolve(old)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ret) != 1 || ret[0] != cur {
+			t.Fatalf("expect %s -> %s but get %v", old, cur, ret)
+		}
+		// 内置数据早于变更记录，解析结果为内置数据中的代码
+		ret, err = s.ResolveCode(old)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ret) != 1 || ret[0] != old {
+			t.Fatalf("expect %s unchanged but get %v", old, ret)
+		}
+		if _, err := s.AreaByCode(ret[0], false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s2 := newDataService(t, []carea.AreaData{
+		{Code: "110000", ParentCode: "0", Level: "1", Name: "北京市"},
+	})
+	if s2.Version() != "" {
+		t.Fatal("expect empty version but get ", s2.Version())
+	}
+}
+
+func TestHistoryVersion(t *testing.T) {
+	h := historyTestHistory()
+	if !reflect.DeepEqual(h.Versions(), []string{"2013", "2016"}) || h.Latest() != "2016" {
+		t.Fatal("unexpected versions ", h.Versions())
+	}
+
+	s, err := carea.NewAreaServiceE(carea.DefaultOpt.SetHistory(h, "2013"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Version() != "2013" {
+		t.Fatal("unexpected version ", s.Version())
+	}
+	a, err := s.AreaByCode("110228", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Name != "密云县" {
+		t.Fatal("unexpected area ", a.Name)
+	}
+
+	s, err = carea.NewAreaServiceE(carea.DefaultOpt.SetHistory(h, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Version() != "2016" {
+		t.Fatal("unexpected version ", s.Version())
+	}
+	_, err = s.AreaByCode("110228", false)
+	if !errors.Is(err, carea.ErrAreaNotFound) {
+		t.Fatal("expect ErrAreaNotFound but get ", err)
+	}
+	// 旧代码通过变更记录找到新区域
+	ad, exact, err := carea.AreaOrAncestor(s, "110228")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exact || ad.Code != "110118" {
+		t.Fatal("unexpected area ", ad.Code, exact)
+	}
+	ret, err := s.ResolveCode("110228")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ret) != 1 || ret[0] != "110118" {
+		t.Fatal("unexpected result ", ret)
+	}
+	if _, err := s.AreaByCode(ret[0], false); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = carea.NewAreaServiceE(carea.DefaultOpt.SetHistory(h, "2020"))
+	if !errors.Is(err, carea.ErrVersionNotFound) {
+		t.Fatal("expect ErrVersionNotFound but get ", err)
+	}
+
+	bh := carea.BuildinHistory()
+	if bh.Latest() != carea.BuildinVersion || len(bh.ChangeLog()) == 0 {
+		t.Fatal("unexpected buildin history ", bh.Versions())
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description:
+
+package test
+
+import (
+	"errors"
+	"github.com/xfali/carea"
+	"github.com/xfali/carea/idcard"
+	"testing"
+)
+
+func TestIDCard(t *testing.T) {
+	d := idcard.NewDecoder(carea.NewAreaService())
+	t.Run("18", func(t *testing.T) {
+		info, err := d.Decode("11010519491231002x")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Region.Code != "110105" || !info.RegionExact {
+			t.Fatal("unexpected region ", info.Region)
+		}
+		if info.Birthday.Format("2006-01-02") != "1949-12-31" || info.Gender != idcard.Female {
+			t.Fatal("unexpected info ", info)
+		}
+	})
+
+	t.Run("15", func(t *testing.T) {
+		info, err := d.Decode("110105491231002")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Number != "11010519491231002X" {
+			t.Fatal("expect 11010519491231002X, got ", info.Number)
+		}
+	})
+
+	t.Run("historical region", func(t *testing.T) {
+		number := "11010419800101001"
+		c, err := idcard.Checksum(number)
+		if err != nil {
+			t.Fatal(err)
+		}
+		info, err := d.Decode(number + string(c))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.RegionExact || info.Region.Code != "110100" || info.Gender != idcard.Male {
+			t.Fatal("unexpected info ", info)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		cases := map[string]error{
+			"110105194912310021": idcard.ErrInvalidChecksum,
+			"1101051949123100":   idcard.ErrInvalidLength,
+			"11010519491231002A": idcard.ErrInvalidFormat,
+			"110105194913310021": idcard.ErrInvalidBirthday,
+		}
+		for number, expect := range cases {
+			if err := idcard.Validate(number); !errors.Is(err, expect) {
+				t.Fatalf("%s: expect %v, got %v", number, expect, err)
+			}
+		}
+
+		number := "99010519491231002"
+		c, _ := idcard.Checksum(number)
+		if _, err := d.Decode(number + string(c)); !errors.Is(err, idcard.ErrInvalidRegion) {
+			t.Fatal("expect ErrInvalidRegion, got ", err)
+		}
+	})
+}
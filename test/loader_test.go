@@ -0,0 +1,89 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description:
+
+package test
+
+import (
+	"embed"
+	"errors"
+	"github.com/xfali/carea"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+//go:embed testdata/beijing.json
+var testdata embed.FS
+
+const beijingJSON = `[
+{"code":"110000","parentCode":"0","level":"1","name":"北京市"},
+{"code":"110100","parentCode":"110000","level":"2","name":"市辖区"},
+{"code":"110101","parentCode":"110100","level":"3","name":"东城区"}
+]`
+
+func checkBeijing(t *testing.T, s carea.AreaService) {
+	a, err := s.AreaByCode("110101", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Name != "东城区" {
+		t.Fatal("unexpected area ", a.Name)
+	}
+	if s.AreaLevelNumber() != 3 {
+		t.Fatal("unexpected level number ", s.AreaLevelNumber())
+	}
+}
+
+func TestLoadFromReader(t *testing.T) {
+	s, err := carea.NewAreaServiceE(carea.DefaultOpt.LoadFromReader(strings.NewReader(beijingJSON)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBeijing(t, s)
+	// 重复读取使用缓存内容
+	d, err := s.Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d) != 3 {
+		t.Fatal("expect 3 records but get ", len(d))
+	}
+
+	_, err = carea.NewAreaServiceE(carea.DefaultOpt.LoadFromReader(strings.NewReader("[")))
+	if !errors.Is(err, carea.ErrInvalidData) {
+		t.Fatal("expect ErrInvalidData but get ", err)
+	}
+}
+
+func TestLoadFromBytes(t *testing.T) {
+	s, err := carea.NewAreaServiceE(carea.DefaultOpt.LoadFromBytes([]byte(beijingJSON)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBeijing(t, s)
+}
+
+func TestLoadFromFS(t *testing.T) {
+	s, err := carea.NewAreaServiceE(carea.DefaultOpt.LoadFromFS(testdata, "testdata/beijing.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBeijing(t, s)
+
+	fsys := fstest.MapFS{
+		"data/areas.json": &fstest.MapFile{Data: []byte(beijingJSON)},
+	}
+	s, err = carea.NewAreaServiceE(carea.DefaultOpt.LoadFromFS(fsys, "data/areas.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBeijing(t, s)
+
+	_, err = carea.NewAreaServiceE(carea.DefaultOpt.LoadFromFS(fsys, "data/none.json"))
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatal("expect fs.ErrNotExist but get ", err)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description:
+
+package test
+
+import (
+	"errors"
+	"github.com/xfali/carea"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+const mcaTable = `2020年中华人民共和国县以上行政区划代码
+行政区划代码	单位名称
+110000	北京市
+110101	东城区
+110118	密云区
+420000	湖北省
+420100	武汉市
+420102	江岸区
+429004	仙桃市
+500000	重庆市
+500101	万州区
+500229	城口县
+650000	新疆维吾尔自治区
+659001	石河子市
+`
+
+func TestLoadMCAData(t *testing.T) {
+	data, err := carea.LoadMCAData(strings.NewReader(mcaTable))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := carea.ValidateData(data)
+	if report.HasError() {
+		t.Fatal(report)
+	}
+
+	index := map[carea.AreaCode]carea.AreaData{}
+	for _, ad := range data {
+		index[ad.Code] = ad
+	}
+	// 12条记录及补充的5条占位记录
+	if len(index) != 17 {
+		t.Fatal("expect 17 records but get ", len(index))
+	}
+	expect := []struct {
+		code, parent carea.AreaCode
+		level        carea.AreaLevel
+		name         string
+	}{
+		{"110000", "0", "1", "北京市"},
+		{"110100", "110000", "2", "市辖区"},
+		{"110118", "110100", "3", "密云区"},
+		{"420102", "420100", "3", "江岸区"},
+		{"429000", "420000", "2", "省直辖县级行政区划"},
+		{"429004", "429000", "3", "仙桃市"},
+		{"500100", "500000", "2", "市辖区"},
+		{"500200", "500000", "2", "市辖县"},
+		{"500229", "500200", "3", "城口县"},
+		{"659000", "650000", "2", "自治区直辖县级行政区划"},
+		{"659001", "659000", "3", "石河子市"},
+	}
+	for _, e := range expect {
+		ad, ok := index[e.code]
+		if !ok {
+			t.Fatal("expect record ", e.code)
+		}
+		if ad.ParentCode != e.parent || ad.Level != e.level || ad.Name != e.name {
+			t.Fatal("unexpected record ", ad)
+		}
+	}
+}
+
+func TestLoadFromMCAFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "mca*.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(mcaTable); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	s, err := carea.NewAreaServiceE(carea.DefaultOpt.LoadFromMCAFile(f.Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, err := s.FullName("500229", carea.DefaultNameOpt.CollapsePlaceholder())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "重庆市城口县" {
+		t.Fatal("unexpected full name ", name)
+	}
+	name, err = s.FullName("429004", carea.DefaultNameOpt.CollapsePlaceholder())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "湖北省仙桃市" {
+		t.Fatal("unexpected full name ", name)
+	}
+
+	_, err = carea.NewAreaServiceE(carea.DefaultOpt.LoadFromMCAFile(f.Name() + ".none"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatal("expect os.ErrNotExist but get ", err)
+	}
+}
+
+func TestLoadMCADataSeparatorLine(t *testing.T) {
+	data, err := carea.LoadMCAData(strings.NewReader(",,,\n110000\t北京市\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 1 || data[0].Code != "110000" {
+		t.Fatal("expect 110000 only, got ", data)
+	}
+}
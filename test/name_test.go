@@ -0,0 +1,65 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description:
+
+package test
+
+import (
+	"github.com/xfali/carea"
+	"testing"
+)
+
+func TestPath(t *testing.T) {
+	s := carea.NewAreaService()
+	path, err := s.Path("510107")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(path) != 3 || path[0].Code != "510000" || path[2].Code != "510107" {
+		t.Fatal("unexpected path ", path)
+	}
+	if _, err := s.Path("999999"); err == nil {
+		t.Fatal("expect error")
+	}
+}
+
+func TestFullName(t *testing.T) {
+	s := carea.NewAreaService()
+	cases := []struct {
+		code carea.AreaCode
+		opts []carea.NameOpt
+		name string
+	}{
+		{"510107", nil, "四川省成都市武侯区"},
+		{"110101", nil, "北京市市辖区东城区"},
+		{"110101", []carea.NameOpt{carea.DefaultNameOpt.CollapsePlaceholder()}, "北京市东城区"},
+		{"429004", []carea.NameOpt{carea.DefaultNameOpt.CollapsePlaceholder()}, "湖北省仙桃市"},
+		{"110100", []carea.NameOpt{carea.DefaultNameOpt.CollapsePlaceholder()}, "北京市市辖区"},
+		{"510107", []carea.NameOpt{carea.DefaultNameOpt.Separator(" ")}, "四川省 成都市 武侯区"},
+	}
+	for _, c := range cases {
+		name, err := s.FullName(c.code, c.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name != c.name {
+			t.Fatalf("%s: expect %s, got %s", c.code, c.name, name)
+		}
+	}
+}
+
+func TestFullNameDropDuplicate(t *testing.T) {
+	s := newDataService(t, []carea.AreaData{
+		{Code: "110000", ParentCode: "0", Level: "1", Name: "北京市"},
+		{Code: "110100", ParentCode: "110000", Level: "2", Name: "北京市"},
+		{Code: "110101", ParentCode: "110100", Level: "3", Name: "东城区"},
+	})
+	name, err := s.FullName("110101", carea.DefaultNameOpt.DropDuplicate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "北京市东城区" {
+		t.Fatal("expect 北京市东城区, got ", name)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description:
+
+package test
+
+import (
+	"github.com/xfali/carea"
+	"strings"
+	"testing"
+)
+
+const nbsTable = `统计用区划代码,城乡分类代码,名称
+110000000000,北京市
+110100000000,市辖区
+110101000000,东城区
+110101001000,东华门街道
+110101001001,111,多福巷社区居委会
+110101001002,111,银闸社区居委会
+440000000000,广东省
+441900000000,东莞市
+441900003000,东城街道
+441900003001,111,花园新村社区居委会
+`
+
+func TestNBSLevels(t *testing.T) {
+	data, err := carea.LoadNBSData(strings.NewReader(nbsTable))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newDataService(t, data)
+	if s.AreaLevelNumber() != 5 {
+		t.Fatal("expect 5 levels, got ", s.AreaLevelNumber())
+	}
+
+	t.Run("code width", func(t *testing.T) {
+		for _, code := range []carea.AreaCode{"110101", "110101000000"} {
+			a, err := s.AreaByCode(code, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if a.Code != "110101" || len(a.Subareas) != 1 || len(a.Subareas[0].Subareas) != 2 {
+				t.Fatal("unexpected ", a)
+			}
+		}
+		for _, code := range []carea.AreaCode{"110101001", "110101001000"} {
+			a, err := s.AreaByCode(code, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if a.Level != carea.TownshipLevel {
+				t.Fatal("expect township, got ", a.Level)
+			}
+		}
+	})
+
+	t.Run("parent", func(t *testing.T) {
+		p, err := s.ParentAreaByCode("110101001001", true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Code != "110000" {
+			t.Fatal("expect 110000, got ", p.Code)
+		}
+		t.Log(p)
+	})
+
+	t.Run("township under prefecture", func(t *testing.T) {
+		p, err := s.ParentAreaByCode("441900003", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Code != "441900" {
+			t.Fatal("expect 441900, got ", p.Code)
+		}
+		report, _ := s.Validate()
+		if report.HasError() {
+			t.Fatal(report)
+		}
+	})
+}
+
+func TestStatCode(t *testing.T) {
+	cases := map[carea.AreaCode]carea.AreaLevel{
+		"110101001001": carea.VillageLevel,
+		"110101001000": carea.TownshipLevel,
+		"110101001":    carea.TownshipLevel,
+		"110101000000": carea.CountyLevel,
+		"110000000000": carea.ProvinceLevel,
+	}
+	for code, lv := range cases {
+		if code.InferredLevel() != lv {
+			t.Fatalf("%s: expect level %s, got %s", code, lv, code.InferredLevel())
+		}
+	}
+	if carea.AreaCode("110101001").StatCode() != "110101001000" {
+		t.Fatal("expect 110101001000")
+	}
+	if carea.AreaCode("110101001001").InferredParentCode() != "110101001" {
+		t.Fatal("expect 110101001")
+	}
+	if carea.AreaCode("110101000001").IsValidFormat() {
+		t.Fatal("village without township should be invalid")
+	}
+}
+
+func TestNBSSeparatorLine(t *testing.T) {
+	data, err := carea.LoadNBSData(strings.NewReader(",,,\n110000000000,北京市\n\t \t\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 1 || data[0].Code != "110000" {
+		t.Fatal("expect 110000 only, got ", data)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description:
+
+package test
+
+import (
+	"errors"
+	"github.com/xfali/carea"
+	"testing"
+)
+
+func newDataService(t *testing.T, data []carea.AreaData) carea.AreaService {
+	s, err := carea.NewAreaServiceE(carea.DefaultOpt.SetDataSource(func() ([]carea.AreaData, error) {
+		return data, nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestParseUnordered(t *testing.T) {
+	s := newDataService(t, []carea.AreaData{
+		{Code: "510104", ParentCode: "510100", Level: "3", Name: "锦江区"},
+		{Code: "510100", ParentCode: "510000", Level: "2", Name: "成都市"},
+		{Code: "510000", ParentCode: "0", Level: "1", Name: "四川省"},
+	})
+	if s.AreaLevelNumber() != 3 {
+		t.Fatal("expect 3 levels, got ", s.AreaLevelNumber())
+	}
+	a, err := s.AreaByCode("510000", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Subareas) != 1 || len(a.Subareas[0].Subareas) != 1 {
+		t.Fatal("expect full tree, got ", a)
+	}
+	t.Log(a)
+}
+
+func TestParseGapped(t *testing.T) {
+	s := newDataService(t, []carea.AreaData{
+		{Code: "510104", ParentCode: "510000", Level: "3", Name: "锦江区"},
+		{Code: "510000", ParentCode: "0", Level: "1", Name: "四川省"},
+	})
+	if s.AreaLevelNumber() != 3 {
+		t.Fatal("expect 3 levels, got ", s.AreaLevelNumber())
+	}
+	if len(s.AreaLevels()) != 2 {
+		t.Fatal("expect 2 present levels, got ", s.AreaLevels())
+	}
+	lv2, err := s.AreaByLevel("2", false)
+	if err != nil || len(lv2) != 0 {
+		t.Fatal("expect empty level 2, got ", lv2, err)
+	}
+	p, err := s.ParentAreaByCode("510104", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Code != "510000" {
+		t.Fatal("expect parent 510000, got ", p.Code)
+	}
+}
+
+func TestParseInvalidLevel(t *testing.T) {
+	_, err := carea.NewAreaServiceE(carea.DefaultOpt.SetDataSource(func() ([]carea.AreaData, error) {
+		return []carea.AreaData{
+			{Code: "510000", ParentCode: "0", Level: "province", Name: "四川省"},
+		}, nil
+	}))
+	if !errors.Is(err, carea.ErrInvalidData) {
+		t.Fatal("expect ErrInvalidData, got ", err)
+	}
+	t.Log(err)
+
+	for _, lv := range []carea.AreaLevel{"0", "6", "99999999999"} {
+		_, err = carea.NewAreaServiceE(carea.DefaultOpt.SetDataSource(func() ([]carea.AreaData, error) {
+			return []carea.AreaData{
+				{Code: "510000", ParentCode: "0", Level: lv, Name: "四川省"},
+			}, nil
+		}))
+		if !errors.Is(err, carea.ErrInvalidData) {
+			t.Fatalf("level %s expect ErrInvalidData, got %v", lv, err)
+		}
+	}
+}
+
+func TestParseCyclicParent(t *testing.T) {
+	s := newDataService(t, []carea.AreaData{
+		{Code: "110000", ParentCode: "0", Level: "1", Name: "北京市", Latitude: "39.904", Longitude: "116.407"},
+		{Code: "110100", ParentCode: "110100", Level: "2", Name: "市辖区"},
+		{Code: "110101", ParentCode: "110100", Level: "3", Name: "东城区", Latitude: "39.917", Longitude: "116.418"},
+		{Code: "120100", ParentCode: "120101", Level: "2", Name: "市辖区"},
+		{Code: "120101", ParentCode: "120100", Level: "3", Name: "和平区", Latitude: "39.117", Longitude: "117.195"},
+	})
+	p, err := s.ParentAreaByCode("110101", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Code != "110100" || len(p.Subareas) != 1 {
+		t.Fatal("expect parent 110100, got ", p)
+	}
+	a, err := s.AreaByCode("110100", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Subareas) != 1 || a.Subareas[0].Code != "110101" || len(a.Subareas[0].Subareas) != 0 {
+		t.Fatal("expect single child 110101, got ", a)
+	}
+	a, err = s.AreaByCode("120101", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Subareas) != 0 {
+		t.Fatal("expect no child, got ", a)
+	}
+	path, err := s.Path("110101")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(path) != 2 || path[0].Code != "110100" {
+		t.Fatal("expect path 110100/110101, got ", path)
+	}
+	path, err = s.Path("120100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(path) != 1 {
+		t.Fatal("expect path 120100, got ", path)
+	}
+	r, err := s.Search("和平", carea.DefaultSearchOpt.Under("120100"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r) != 1 {
+		t.Fatal("expect 1 result, got ", r)
+	}
+	n, err := s.NearestArea(39.917, 116.418, carea.AnyLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.Code != "110101" || len(n.Path) != 2 {
+		t.Fatal("expect 110101 with path, got ", n)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description:
+
+package test
+
+import (
+	"errors"
+	"github.com/xfali/carea"
+	"testing"
+)
+
+func TestAreaByPath(t *testing.T) {
+	s := carea.NewAreaService()
+	t.Run("full", func(t *testing.T) {
+		a, err := s.AreaByPath("北京市/市辖区/朝阳区", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.Code != "110105" {
+			t.Fatal("expect 110105, got ", a.Code)
+		}
+	})
+
+	t.Run("skip placeholder", func(t *testing.T) {
+		_, err := s.AreaByPath("北京市/朝阳区", false)
+		if !errors.Is(err, carea.ErrAreaNotFound) {
+			t.Fatal("expect ErrAreaNotFound, got ", err)
+		}
+		a, err := s.AreaByPath("北京/朝阳", false, carea.DefaultPathOpt.SkipPlaceholder())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.Code != "110105" {
+			t.Fatal("expect 110105, got ", a.Code)
+		}
+	})
+
+	t.Run("separator", func(t *testing.T) {
+		a, err := s.AreaByPath("四川省>成都市>武侯区", true, carea.DefaultPathOpt.Separator(">"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.Code != "510107" {
+			t.Fatal("expect 510107, got ", a.Code)
+		}
+	})
+
+	t.Run("ambiguous", func(t *testing.T) {
+		_, err := s.AreaByPath("朝阳区", false)
+		if !errors.Is(err, carea.ErrAmbiguousArea) {
+			t.Fatal("expect ErrAmbiguousArea, got ", err)
+		}
+		var ae *carea.AreaError
+		if !errors.As(err, &ae) || len(ae.Candidates) != 2 {
+			t.Fatal("expect 2 candidates, got ", err)
+		}
+		t.Log(err)
+
+		a, err := s.AreaByPath("长春市/朝阳区", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.Code != "220104" {
+			t.Fatal("expect 220104, got ", a.Code)
+		}
+	})
+}
+
+func TestAreaByNameUnder(t *testing.T) {
+	s := carea.NewAreaService()
+	a, err := s.AreaByNameUnder("110000", "朝阳区", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Code != "110105" {
+		t.Fatal("expect 110105, got ", a.Code)
+	}
+
+	_, err = s.AreaByNameUnder("510000", "市辖区", false)
+	if !errors.Is(err, carea.ErrAmbiguousArea) {
+		t.Fatal("expect ErrAmbiguousArea, got ", err)
+	}
+
+	_, err = s.AreaByNameUnder("510000", "朝阳区", false)
+	if !errors.Is(err, carea.ErrAreaNotFound) {
+		t.Fatal("expect ErrAreaNotFound, got ", err)
+	}
+}
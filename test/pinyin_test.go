@@ -0,0 +1,110 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description:
+
+package test
+
+import (
+	"github.com/xfali/carea"
+	"testing"
+	"unicode"
+)
+
+func TestPinyin(t *testing.T) {
+	cases := map[string]string{
+		"成都市":   "chengdushi",
+		"重庆市":   "chongqingshi",
+		"蚌埠市":   "bengbushi",
+		"蚌山区":   "bengshanqu",
+		"称多县":   "chenduoxian",
+		"六安市":   "luanshi",
+		"长沙市":   "changshashi",
+		"西藏自治区": "xizangzizhiqu",
+		"厦门市":   "xiamenshi",
+		"吕梁市":   "lvliangshi",
+		"红寺堡区":  "hongsibuqu",
+		"乌鲁木齐市": "wulumuqishi",
+		"浚县":    "xunxian",
+		"长子县":   "zhangzixian",
+		"番禺区":   "panyuqu",
+		"佛山市":   "foshanshi",
+	}
+	for name, py := range cases {
+		if carea.FullPinyin(name) != py {
+			t.Fatalf("%s: expect %s, got %s", name, py, carea.FullPinyin(name))
+		}
+	}
+	if carea.PinyinInitials("成都市") != "cds" {
+		t.Fatal("expect cds, got ", carea.PinyinInitials("成都市"))
+	}
+}
+
+func TestPinyinCoverage(t *testing.T) {
+	s := carea.NewAreaService()
+	v, err := s.Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range v {
+		han := 0
+		for _, r := range a.Name {
+			if unicode.Is(unicode.Han, r) {
+				han++
+			}
+		}
+		if len(carea.ToPinyin(a.Name)) < han {
+			t.Fatalf("%s: missing pinyin %v", a.Name, carea.ToPinyin(a.Name))
+		}
+	}
+	a, err := s.AreaByCode("510100", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Pinyin != "chengdushi" || a.PinyinInitials != "cds" {
+		t.Fatal("unexpected pinyin ", a.Pinyin, a.PinyinInitials)
+	}
+}
+
+func TestSearchPinyin(t *testing.T) {
+	s := carea.NewAreaService()
+	for kw, code := range map[string]carea.AreaCode{
+		"chengdu":   "510100",
+		"sichuan":   "510000",
+		"chongqing": "500000",
+		"bengbu":    "340300",
+		"bengshan":  "340303",
+		"chenduo":   "632723",
+		"Cheng Du":  "510100",
+	} {
+		ret, err := s.Search(kw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ret) == 0 || ret[0].Code != code || ret[0].Mode != carea.MatchPinyin {
+			t.Fatalf("%s: expect %s first, got %v", kw, code, ret)
+		}
+	}
+
+	ret, err := s.Search("cd", carea.DefaultSearchOpt.Level("2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, r := range ret {
+		if r.Code == "510100" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("expect 510100 in ", ret)
+	}
+
+	ret, err = s.Search("chengdu", carea.DefaultSearchOpt.Mode(carea.MatchExact|carea.MatchPrefix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ret) != 0 {
+		t.Fatal("expect no result without pinyin mode, got ", ret)
+	}
+}
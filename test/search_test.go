@@ -0,0 +1,104 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description:
+
+package test
+
+import (
+	"github.com/xfali/carea"
+	"testing"
+)
+
+func TestShortName(t *testing.T) {
+	cases := map[string]string{
+		"四川省":             "四川",
+		"北京市":             "北京",
+		"广西壮族自治区":         "广西",
+		"新疆维吾尔自治区":        "新疆",
+		"内蒙古自治区":          "内蒙古",
+		"凉山彝族自治州":         "凉山",
+		"伊犁哈萨克自治州":        "伊犁",
+		"民和回族土族自治县":       "民和",
+		"东乡族自治县":          "东乡族",
+		"锡林郭勒盟":           "锡林郭勒",
+		"香港特别行政区":         "香港",
+		"大兴安岭地区":          "大兴安岭",
+		"科尔沁右翼中旗":         "科尔沁右翼中",
+		"双江拉祜族佤族布朗族傣族自治县": "双江",
+	}
+	for name, short := range cases {
+		if carea.ShortName(name) != short {
+			t.Fatalf("%s: expect %s, got %s", name, short, carea.ShortName(name))
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	s := carea.NewAreaService()
+	t.Run("suffix", func(t *testing.T) {
+		for kw, code := range map[string]carea.AreaCode{
+			"四川":  "510000",
+			"广西":  "450000",
+			"四川省": "510000",
+		} {
+			ret, err := s.Search(kw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ret) == 0 || ret[0].Code != code {
+				t.Fatalf("%s: expect %s first, got %v", kw, code, ret)
+			}
+		}
+	})
+
+	t.Run("mode", func(t *testing.T) {
+		ret, err := s.Search("四川", carea.DefaultSearchOpt.Mode(carea.MatchExact))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ret) != 0 {
+			t.Fatal("expect no exact match, got ", ret)
+		}
+		ret, err = s.Search("成都", carea.DefaultSearchOpt.Mode(carea.MatchPrefix))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ret) != 1 || ret[0].Code != "510100" || ret[0].Mode != carea.MatchPrefix {
+			t.Fatal("expect 510100, got ", ret)
+		}
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		ret, err := s.Search("朝阳")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ret) < 3 {
+			t.Fatal("expect several 朝阳, got ", ret)
+		}
+		for i := 1; i < len(ret); i++ {
+			if ret[i].Score > ret[i-1].Score {
+				t.Fatal("results not ranked ", ret)
+			}
+		}
+		ret, err = s.Search("朝阳", carea.DefaultSearchOpt.Under("110000"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ret) != 1 || ret[0].Code != "110105" {
+			t.Fatal("expect 110105, got ", ret)
+		}
+		ret, err = s.Search("朝阳", carea.DefaultSearchOpt.Level("2"), carea.DefaultSearchOpt.Limit(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ret) != 1 || ret[0].Code != "211300" {
+			t.Fatal("expect 211300, got ", ret)
+		}
+		_, err = s.Search("朝阳", carea.DefaultSearchOpt.Under("999999"))
+		if err == nil {
+			t.Fatal("expect error")
+		}
+	})
+}
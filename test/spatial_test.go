@@ -0,0 +1,268 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description:
+
+package test
+
+import (
+	"errors"
+	"github.com/xfali/carea"
+	"math"
+	"testing"
+)
+
+func spatialTestData() []carea.AreaData {
+	return []carea.AreaData{
+		{Code: "510000", ParentCode: "0", Level: "1", Name: "四川省", Latitude: "30.651226", Longitude: "104.075881"},
+		{Code: "510100", ParentCode: "510000", Level: "2", Name: "成都市", Latitude: "30.659462", Longitude: "104.065735"},
+		{Code: "510101", ParentCode: "510100", Level: "3", Name: "市辖区", Latitude: "30.66", Longitude: "104.06"},
+		{Code: "510104", ParentCode: "510100", Level: "3", Name: "锦江区", Latitude: "30.657689", Longitude: "104.083347"},
+		{Code: "510105", ParentCode: "510100", Level: "3", Name: "青羊区", Latitude: "30.667648", Longitude: "104.055731"},
+		{Code: "510112", ParentCode: "510100", Level: "3", Name: "龙泉驿区", Latitude: "30.56065", Longitude: "104.269181"},
+		{Code: "510113", ParentCode: "510100", Level: "3", Name: "青白江区"},
+		{Code: "510300", ParentCode: "510000", Level: "2", Name: "自贡市", Latitude: "29.352765", Longitude: "104.773447"},
+		{Code: "510302", ParentCode: "510300", Level: "3", Name: "自流井区", Latitude: "29.343231", Longitude: "104.778188"},
+		{Code: "510303", ParentCode: "510300", Level: "3", Name: "贡井区", Latitude: "0.345675", Longitude: "104.714372"},
+	}
+}
+
+func TestNearestArea(t *testing.T) {
+	s := newDataService(t, spatialTestData())
+	a, err := s.NearestArea(30.66, 104.06, carea.CountyLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Code != "510105" {
+		t.Fatal("expect 青羊区 but get ", a.Name)
+	}
+	if a.Distance <= 0 || a.Distance > 1 {
+		t.Fatal("unexpected distance ", a.Distance)
+	}
+	if len(a.Path) != 3 || a.Path[0].Code != "510000" || a.Path[1].Code != "510100" || a.Path[2].Code != "510105" {
+		t.Fatal("unexpected path ", a.Path)
+	}
+
+	a, err = s.NearestArea(29.3, 104.7, carea.PrefectureLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Code != "510300" {
+		t.Fatal("expect 自贡市 but get ", a.Name)
+	}
+
+	a, err = s.NearestArea(29.3, 104.7, carea.AnyLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Code != "510302" {
+		t.Fatal("expect 自流井区 but get ", a.Name)
+	}
+}
+
+func TestKNearest(t *testing.T) {
+	s := newDataService(t, spatialTestData())
+	ret, err := s.KNearest(30.66, 104.06, 10, carea.CountyLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 青白江区无坐标，贡井区坐标超出范围
+	expect := []carea.AreaCode{"510105", "510104", "510112", "510302"}
+	if len(ret) != len(expect) {
+		t.Fatal("unexpected result ", ret)
+	}
+	for i, a := range ret {
+		if a.Code != expect[i] {
+			t.Fatalf("index %d expect %s but get %s", i, expect[i], a.Code)
+		}
+		if i > 0 && a.Distance < ret[i-1].Distance {
+			t.Fatal("result not sorted by distance")
+		}
+	}
+
+	ret, err = s.KNearest(30.66, 104.06, 2, carea.AnyLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ret) != 2 || ret[0].Code != "510100" || ret[1].Code != "510105" {
+		t.Fatal("unexpected result ", ret)
+	}
+}
+
+func TestKNearestBuildin(t *testing.T) {
+	s := carea.NewAreaService()
+	ret, err := s.KNearest(30.66, 104.06, 5, carea.CountyLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ret) != 5 {
+		t.Fatal("expect 5 results but get ", len(ret))
+	}
+	for _, a := range ret {
+		if !a.HasLocation() || a.Distance > 50 {
+			t.Fatal("unexpected result ", a.Name, a.Distance)
+		}
+		t.Log(a.Code, a.Name, a.Distance)
+	}
+}
+
+func TestNearestAreaSuspectCoordinate(t *testing.T) {
+	s := carea.NewAreaService()
+	// 丰台区的纬度被截断为9.84，该点位于南海
+	a, err := s.NearestArea(9.9, 116.3, carea.CountyLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Code == "110106" || a.Distance < 100 {
+		t.Fatal("unexpected result ", a.Code, a.Name, a.Distance)
+	}
+
+	report, err := s.Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	suspect := append(report.ByType(carea.IssueFarFromParent), report.ByType(carea.IssueOutOfBounds)...)
+	if len(suspect) == 0 {
+		t.Fatal("expect suspect coordinates in buildin data")
+	}
+	for _, i := range suspect {
+		ad, err := s.AreaByCode(i.Code, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lat, lng, ok := ad.Location()
+		if !ok || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+			continue
+		}
+		ret, err := s.AreasWithinRadius(lat, lng, 0.001, carea.AnyLevel)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range ret {
+			if r.Code == ad.Code {
+				t.Fatal("suspect coordinate indexed ", i)
+			}
+		}
+	}
+}
+
+func TestNearestAreaError(t *testing.T) {
+	s := newDataService(t, spatialTestData())
+	_, err := s.NearestArea(91, 104.06, carea.CountyLevel)
+	if !errors.Is(err, carea.ErrInvalidCoordinate) {
+		t.Fatal("expect ErrInvalidCoordinate but get ", err)
+	}
+	for _, c := range [][2]float64{{math.NaN(), math.NaN()}, {30.66, math.NaN()}, {math.Inf(1), 104.06}, {30.66, math.Inf(-1)}} {
+		_, err = s.NearestArea(c[0], c[1], carea.AnyLevel)
+		if !errors.Is(err, carea.ErrInvalidCoordinate) {
+			t.Fatal("expect ErrInvalidCoordinate but get ", err)
+		}
+	}
+	_, err = s.NearestArea(30.66, 104.06, carea.AreaLevel("4"))
+	if !errors.Is(err, carea.ErrLevelOutOfRange) {
+		t.Fatal("expect ErrLevelOutOfRange but get ", err)
+	}
+
+	s = newDataService(t, []carea.AreaData{
+		{Code: "510000", ParentCode: "0", Level: "1", Name: "四川省"},
+	})
+	_, err = s.NearestArea(30.66, 104.06, carea.ProvinceLevel)
+	if !errors.Is(err, carea.ErrAreaNotFound) {
+		t.Fatal("expect ErrAreaNotFound but get ", err)
+	}
+}
+
+func TestAreasWithinRadius(t *testing.T) {
+	s := newDataService(t, spatialTestData())
+	ret, err := s.AreasWithinRadius(30.66, 104.06, 25, carea.CountyLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 市辖区为占位区域，不参与空间查询
+	expect := []carea.AreaCode{"510105", "510104", "510112"}
+	if len(ret) != len(expect) {
+		t.Fatal("unexpected result ", ret)
+	}
+	for i, a := range ret {
+		if a.Code != expect[i] {
+			t.Fatalf("index %d expect %s but get %s", i, expect[i], a.Code)
+		}
+		if a.Distance > 25 {
+			t.Fatal("distance out of radius ", a.Distance)
+		}
+	}
+
+	ret, err = s.AreasWithinRadius(30.66, 104.06, 5, carea.AnyLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ret) != 4 || ret[0].Code != "510100" {
+		t.Fatal("unexpected result ", ret)
+	}
+
+	ret, err = s.AreasWithinRadius(30.66, 104.06, 0, carea.CountyLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ret) != 0 {
+		t.Fatal("expect empty result but get ", ret)
+	}
+
+	_, err = s.AreasWithinRadius(30.66, 104.06, -1, carea.CountyLevel)
+	if !errors.Is(err, carea.ErrInvalidCoordinate) {
+		t.Fatal("expect ErrInvalidCoordinate but get ", err)
+	}
+}
+
+func TestAreasWithinRadiusBuildin(t *testing.T) {
+	s := carea.NewAreaService()
+	ret, err := s.AreasWithinRadius(30.66, 104.06, 50, carea.CountyLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ret) == 0 {
+		t.Fatal("expect counties around Chengdu")
+	}
+	for i, a := range ret {
+		if a.Distance > 50 || (i > 0 && a.Distance < ret[i-1].Distance) {
+			t.Fatal("unexpected result ", a.Name, a.Distance)
+		}
+		if a.IsPlaceholder() {
+			t.Fatal("unexpected placeholder ", a.Code)
+		}
+	}
+}
+
+func TestAreasInBBox(t *testing.T) {
+	s := newDataService(t, spatialTestData())
+	ret, err := s.AreasInBBox(30.5, 104.0, 30.7, 104.3, carea.CountyLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []carea.AreaCode{"510104", "510105", "510112"}
+	if len(ret) != len(expect) {
+		t.Fatal("unexpected result ", ret)
+	}
+	for i, a := range ret {
+		if a.Code != expect[i] {
+			t.Fatalf("index %d expect %s but get %s", i, expect[i], a.Code)
+		}
+	}
+
+	ret, err = s.AreasInBBox(29, 104, 31, 105, carea.PrefectureLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ret) != 2 {
+		t.Fatal("unexpected result ", ret)
+	}
+
+	_, err = s.AreasInBBox(31, 104, 30, 105, carea.CountyLevel)
+	if !errors.Is(err, carea.ErrInvalidCoordinate) {
+		t.Fatal("expect ErrInvalidCoordinate but get ", err)
+	}
+	_, err = s.AreasInBBox(29, 104, 31, 105, carea.AreaLevel("9"))
+	if !errors.Is(err, carea.ErrLevelOutOfRange) {
+		t.Fatal("expect ErrLevelOutOfRange but get ", err)
+	}
+}
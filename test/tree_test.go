@@ -0,0 +1,123 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description:
+
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/xfali/carea"
+	"testing"
+)
+
+func TestLoadTreeFromAreaString(t *testing.T) {
+	s := carea.NewAreaService()
+	a, err := s.AreaByCode("510000", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, err := carea.NewAreaServiceE(carea.DefaultOpt.LoadFromBytes([]byte(a.String())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a2, err := s2.AreaByCode("510000", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.String() != a2.String() {
+		t.Fatal("tree round trip mismatch")
+	}
+}
+
+func TestLoadTreeFromAreas(t *testing.T) {
+	s := carea.NewAreaService()
+	areas, err := s.Areas(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(areas)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, err := carea.NewAreaServiceE(carea.DefaultOpt.LoadFromBytes(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d1, _ := s.Data()
+	d2, _ := s2.Data()
+	if len(d1) != len(d2) {
+		t.Fatalf("expect %d records but get %d", len(d1), len(d2))
+	}
+	for _, ad := range d1 {
+		a, err := s2.AreaByCode(ad.Code, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.ParentCode != ad.ParentCode || a.Level != ad.Level || a.Name != ad.Name {
+			t.Fatal("unexpected area ", a.AreaData)
+		}
+	}
+}
+
+func TestLoadTreeFillHierarchy(t *testing.T) {
+	tree := `{"subareas": [
+		{"code": "510000", "name": "四川省", "subareas": [
+			{"code": "510100", "name": "成都市", "subareas": [
+				{"code": "510104", "name": "锦江区"},
+				{"code": "510105", "name": "青羊区", "level": "3", "parentCode": "510100"}
+			]}
+		]},
+		{"code": "500000", "name": "重庆市"}
+	]}`
+	s, err := carea.NewAreaServiceE(carea.DefaultOpt.LoadFromBytes([]byte(tree)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := s.Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []carea.AreaData{
+		{Code: "510000", ParentCode: "0", Level: "1", Name: "四川省"},
+		{Code: "510100", ParentCode: "510000", Level: "2", Name: "成都市"},
+		{Code: "510104", ParentCode: "510100", Level: "3", Name: "锦江区"},
+		{Code: "510105", ParentCode: "510100", Level: "3", Name: "青羊区"},
+		{Code: "500000", ParentCode: "0", Level: "1", Name: "重庆市"},
+	}
+	if len(d) != len(expect) {
+		t.Fatal("unexpected data ", d)
+	}
+	for i, ad := range d {
+		e := expect[i]
+		if ad.Code != e.Code || ad.ParentCode != e.ParentCode || ad.Level != e.Level || ad.Name != e.Name {
+			t.Fatalf("index %d expect %v but get %v", i, e, ad)
+		}
+	}
+}
+
+func TestLoadFlatFilled(t *testing.T) {
+	for _, d := range []string{
+		// 扁平数据缺失层级及父区域
+		`[{"code": "510000", "name": "四川省"}, {"code": "510100", "name": "成都市"}]`,
+		// 只有叶子节点的树形数据
+		`[{"code": "510000", "name": "四川省", "subareas": []}, {"code": "510100", "name": "成都市", "subareas": null}]`,
+	} {
+		s, err := carea.NewAreaServiceE(carea.DefaultOpt.LoadFromBytes([]byte(d)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		a, err := s.AreaByCode("510100", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.Level != carea.PrefectureLevel || a.ParentCode != "510000" {
+			t.Fatal("unexpected area ", a)
+		}
+	}
+	_, err := carea.NewAreaServiceE(carea.DefaultOpt.LoadFromBytes([]byte(`{"code": 1}`)))
+	if !errors.Is(err, carea.ErrInvalidData) {
+		t.Fatal("expect ErrInvalidData but get ", err)
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description:
+
+package test
+
+import (
+	"errors"
+	"github.com/xfali/carea"
+	"github.com/xfali/carea/uscc"
+	"testing"
+)
+
+func TestUSCC(t *testing.T) {
+	d := uscc.NewDecoder(carea.NewAreaService())
+	t.Run("decode", func(t *testing.T) {
+		info, err := d.Decode("91350100m000100y43")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Region.Code != "350100" || !info.RegionExact {
+			t.Fatal("unexpected region ", info.Region)
+		}
+		if info.Authority != '9' || info.OrganizationType != '1' || info.OrganizationCode != "M000100Y4" {
+			t.Fatal("unexpected info ", info)
+		}
+		t.Log(info.AuthorityName, info.Region.Name)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		cases := map[string]error{
+			"91350100M000100Y44": uscc.ErrInvalidChecksum,
+			"91350100M000100Y4":  uscc.ErrInvalidLength,
+			"91350100M0001I0Y43": uscc.ErrInvalidFormat,
+		}
+		for code, expect := range cases {
+			if err := uscc.Validate(code); !errors.Is(err, expect) {
+				t.Fatalf("%s: expect %v, got %v", code, expect, err)
+			}
+		}
+		if _, err := d.Decode("91999900MA00000006"); !errors.Is(err, uscc.ErrInvalidRegion) {
+			t.Fatal("expect ErrInvalidRegion, got ", err)
+		}
+	})
+}
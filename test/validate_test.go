@@ -0,0 +1,111 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description:
+
+package test
+
+import (
+	"errors"
+	"github.com/xfali/carea"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	s := carea.NewAreaService()
+	report, err := s.Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.HasError() {
+		t.Fatal(report.BySeverity(carea.SeverityError))
+	}
+	if len(report.ByType(carea.IssueMissingCoordinate)) == 0 {
+		t.Fatal("expect missing coordinates")
+	}
+	found := false
+	for _, i := range report.BySeverity(carea.SeverityWarning) {
+		if i.Code == "110102" {
+			found = true
+			t.Log(i)
+		}
+	}
+	if !found {
+		t.Fatal("expect truncated latitude of 110102 reported")
+	}
+	t.Logf("total: %d, issues: %d", report.Total, len(report.Issues))
+}
+
+func TestValidateData(t *testing.T) {
+	data := []carea.AreaData{
+		{Code: "110000", ParentCode: "0", Level: "1", Name: "北京市"},
+		{Code: "110100", ParentCode: "110000", Level: "2", Name: "市辖区"},
+		{Code: "110100", ParentCode: "110000", Level: "2", Name: "市辖区"},
+		{Code: "120101", ParentCode: "110100", Level: "3", Name: "和平区"},
+		{Code: "110102", ParentCode: "110100", Level: "4", Name: "西城区"},
+		{Code: "110105", ParentCode: "110000", Level: "3", Name: "朝阳区"},
+		{Code: "130101", ParentCode: "130100", Level: "3", Name: "市辖区"},
+	}
+	report := carea.ValidateData(data)
+	for tp, n := range map[carea.IssueType]int{
+		carea.IssueDuplicateCode:  1,
+		carea.IssuePrefixMismatch: 1,
+		carea.IssueLevelMismatch:  2,
+		carea.IssueOrphanParent:   1,
+	} {
+		if len(report.ByType(tp)) != n {
+			t.Fatalf("expect %d %s, got %v", n, tp, report.ByType(tp))
+		}
+	}
+	t.Log(report)
+
+	_, err := carea.NewAreaServiceE(carea.DefaultOpt.SetDataSource(func() ([]carea.AreaData, error) {
+		return data, nil
+	}), carea.DefaultOpt.Strict())
+	if !errors.Is(err, carea.ErrInvalidData) {
+		t.Fatal("expect ErrInvalidData, got ", err)
+	}
+	t.Log(err)
+}
+
+func TestValidateParentCycle(t *testing.T) {
+	data := []carea.AreaData{
+		{Code: "110100", ParentCode: "110100", Level: "2", Name: "市辖区"},
+		{Code: "110101", ParentCode: "110100", Level: "3", Name: "东城区", Latitude: "39.917", Longitude: "116.418"},
+		{Code: "120100", ParentCode: "120101", Level: "2", Name: "市辖区"},
+		{Code: "120101", ParentCode: "120100", Level: "3", Name: "和平区", Latitude: "39.117", Longitude: "117.195"},
+	}
+	report := carea.ValidateData(data)
+	cycles := report.ByType(carea.IssueParentCycle)
+	if len(cycles) != 3 {
+		t.Fatal("expect 3 parent cycles, got ", cycles)
+	}
+	for _, i := range cycles {
+		if i.Code == "110101" || i.Severity != carea.SeverityError {
+			t.Fatal("unexpected issue ", i)
+		}
+	}
+	t.Log(report)
+
+	_, err := carea.NewAreaServiceE(carea.DefaultOpt.SetDataSource(func() ([]carea.AreaData, error) {
+		return data, nil
+	}), carea.DefaultOpt.Strict())
+	if !errors.Is(err, carea.ErrInvalidData) {
+		t.Fatal("expect ErrInvalidData, got ", err)
+	}
+}
+
+func TestValidateTownshipSkipLevel(t *testing.T) {
+	data := []carea.AreaData{
+		{Code: "440000", ParentCode: "0", Level: "1", Name: "广东省"},
+		{Code: "441900", ParentCode: "440000", Level: "2", Name: "东莞市"},
+		{Code: "441900003", ParentCode: "441900", Level: "4", Name: "东城街道"},
+		{Code: "441901", ParentCode: "440000", Level: "3", Name: "莞城区"},
+	}
+	report := carea.ValidateData(data)
+	issues := report.ByType(carea.IssueLevelMismatch)
+	if len(issues) != 1 || issues[0].Code != "441901" {
+		t.Fatal("expect level mismatch of 441901 only, got ", issues)
+	}
+	t.Log(report)
+}
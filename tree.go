@@ -0,0 +1,69 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description: 树形（嵌套subareas）区域数据的加载
+
+package carea
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// 将树形区域数据按先序展开为扁平记录，下级区域缺失的ParentCode取上级区域的Code，
+// 缺失的Level取上级区域层级加1；顶层区域缺失的ParentCode与Level根据代码结构推导
+func FlattenAreas(areas []Area) []AreaData {
+	var ret []AreaData
+	var walk func(a Area, parent *AreaData)
+	walk = func(a Area, parent *AreaData) {
+		ad := a.AreaData
+		if parent == nil {
+			if ad.ParentCode == "" {
+				ad.ParentCode = ad.Code.InferredParentCode()
+			}
+			if ad.Level == "" {
+				ad.Level = ad.Code.InferredLevel()
+			}
+		} else {
+			if ad.ParentCode == "" {
+				ad.ParentCode = parent.Code
+			}
+			if ad.Level == "" {
+				if lv, err := parent.Level.ParseInt(); err == nil {
+					ad.Level = Int2AreaLevel(lv + 1)
+				}
+			}
+		}
+		ret = append(ret, ad)
+		for _, sub := range a.Subareas {
+			walk(sub, &ad)
+		}
+	}
+	for _, a := range areas {
+		walk(a, nil)
+	}
+	return ret
+}
+
+// 解析JSON格式的区域数据，可为扁平的[]AreaData数组或树形数据：
+// 树形数据可为单个Area（如Area.String()的输出）或[]Area（如AreaByLevel的结果），
+// 没有Code的根节点（仅用于包含subareas）被忽略；扁平数组的记录视为没有下级区域的顶层节点，
+// 缺失的ParentCode与Level同样根据代码结构推导，见FlattenAreas
+func loadFromData(data []byte) ([]AreaData, error) {
+	data = bytes.TrimLeft(data, " \t\r\n\ufeff")
+	var areas []Area
+	if len(data) > 0 && data[0] == '{' {
+		var root Area
+		if err := json.Unmarshal(data, &root); err != nil {
+			return nil, invalidDataError(err)
+		}
+		if root.Code == "" {
+			areas = root.Subareas
+		} else {
+			areas = []Area{root}
+		}
+	} else if err := json.Unmarshal(data, &areas); err != nil {
+		return nil, invalidDataError(err)
+	}
+	return FlattenAreas(areas), nil
+}
@@ -0,0 +1,141 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description: 统一社会信用代码（GB 32100）校验与解析：
+// 第1位为登记管理部门代码，第2位为机构类别代码，第3-8位为登记管理机关行政区划代码，
+// 第9-17位为主体标识码（组织机构代码），第18位为校验码
+
+package uscc
+
+import (
+	"errors"
+	"fmt"
+	"github.com/xfali/carea"
+	"strings"
+)
+
+const Length = 18
+
+var (
+	ErrInvalidLength   = errors.New("credit code length must be 18")
+	ErrInvalidFormat   = errors.New("credit code contains invalid characters")
+	ErrInvalidChecksum = errors.New("credit code checksum mismatch")
+	ErrInvalidRegion   = errors.New("credit code region invalid")
+)
+
+// 代码字符集，不使用I、O、S、V、Z
+const charset = "0123456789ABCDEFGHJKLMNPQRTUWXY"
+
+var weights = [Length - 1]int{1, 3, 9, 27, 19, 26, 16, 17, 20, 29, 25, 13, 8, 24, 10, 30, 28}
+
+// 登记管理部门
+var authorities = map[byte]string{
+	'1': "机构编制",
+	'2': "外交",
+	'3': "司法行政",
+	'4': "文化",
+	'5': "民政",
+	'6': "旅游",
+	'7': "宗教",
+	'8': "工会",
+	'9': "市场监督管理",
+	'A': "中央军委改革和编制办公室",
+	'N': "农业",
+	'Y': "其他",
+}
+
+type Info struct {
+	Code string `json:"code"`
+	// 登记管理部门代码
+	Authority byte `json:"authority"`
+	// 登记管理部门名称，未知代码为空
+	AuthorityName string `json:"authorityName"`
+	// 机构类别代码
+	OrganizationType byte `json:"organizationType"`
+	// 主体标识码（组织机构代码）
+	OrganizationCode string `json:"organizationCode"`
+	// 登记管理机关行政区划代码
+	RegionCode carea.AreaCode `json:"regionCode"`
+	// 登记管理机关所在区域，区划代码已撤销时为仍存在的最近上级区域
+	Region *carea.AreaData `json:"region,omitempty"`
+	// 区划代码是否在当前数据中存在，为false时Region为上级区域
+	RegionExact bool `json:"regionExact"`
+}
+
+// 计算校验码，code至少包含前17位
+func Checksum(code string) (byte, error) {
+	if len(code) < Length-1 {
+		return 0, ErrInvalidLength
+	}
+	sum := 0
+	for i, w := range weights {
+		v := strings.IndexByte(charset, code[i])
+		if v < 0 {
+			return 0, ErrInvalidFormat
+		}
+		sum += v * w
+	}
+	return charset[(31-sum%31)%31], nil
+}
+
+// 校验代码格式、校验码及区划代码格式，不检查区划代码是否存在
+func Validate(code string) error {
+	_, err := parse(code)
+	return err
+}
+
+type Decoder struct {
+	s carea.AreaService
+}
+
+// 创建使用指定区域服务解析登记地的解码器
+func NewDecoder(s carea.AreaService) *Decoder {
+	return &Decoder{s: s}
+}
+
+// 校验并解析代码，区划代码不存在时依次查找其地级、省级区域，均不存在时返回ErrInvalidRegion
+func (d *Decoder) Decode(code string) (*Info, error) {
+	ret, err := parse(code)
+	if err != nil {
+		return nil, err
+	}
+	a, exact, err := carea.AreaOrAncestor(d.s, ret.RegionCode)
+	if err != nil {
+		if errors.Is(err, carea.ErrAreaNotFound) {
+			return nil, fmt.Errorf("%w: region %s not found", ErrInvalidRegion, ret.RegionCode)
+		}
+		return nil, err
+	}
+	ret.Region = &a
+	ret.RegionExact = exact
+	return ret, nil
+}
+
+func parse(code string) (*Info, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != Length {
+		return nil, ErrInvalidLength
+	}
+	if strings.IndexByte(charset, code[Length-1]) < 0 {
+		return nil, ErrInvalidFormat
+	}
+	c, err := Checksum(code)
+	if err != nil {
+		return nil, err
+	}
+	if c != code[Length-1] {
+		return nil, ErrInvalidChecksum
+	}
+	region := carea.AreaCode(code[2:8])
+	if !region.IsValidFormat() {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRegion, region)
+	}
+	return &Info{
+		Code:             code,
+		Authority:        code[0],
+		AuthorityName:    authorities[code[0]],
+		OrganizationType: code[1],
+		OrganizationCode: code[8:17],
+		RegionCode:       region,
+	}, nil
+}
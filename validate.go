@@ -0,0 +1,248 @@
+// Copyright (C) 2019-2022, Xiongfa Li.
+// @author xiongfa.li
+// @version V1.0
+// Description:
+
+package carea
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// 顶级区域的父区域Code
+	RootParentCode = AreaCode("0")
+
+	// 与父区域坐标距离超过该值（公里）的坐标视为可疑
+	maxParentDistanceKm = 1000.0
+)
+
+type IssueType string
+
+const (
+	// 父区域不存在
+	IssueOrphanParent IssueType = "orphan_parent"
+	// 区域Code重复
+	IssueDuplicateCode IssueType = "duplicate_code"
+	// 父区域关系成环，如父区域为自身
+	IssueParentCycle IssueType = "parent_cycle"
+	// 区域层级与父区域层级不连续
+	IssueLevelMismatch IssueType = "level_mismatch"
+	// 区域Code与父区域Code前缀不一致
+	IssuePrefixMismatch IssueType = "prefix_mismatch"
+	// 坐标超出中国范围或无法解析
+	IssueOutOfBounds IssueType = "out_of_bounds"
+	// 坐标与父区域坐标相距过远
+	IssueFarFromParent IssueType = "far_from_parent"
+	// 坐标缺失
+	IssueMissingCoordinate IssueType = "missing_coordinate"
+)
+
+type Severity int
+
+const (
+	SeverityWarning Severity = iota
+	SeverityError
+)
+
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+// 中国经纬度范围（含南海诸岛）
+var ChinaBounds = struct {
+	MinLatitude, MaxLatitude   float64
+	MinLongitude, MaxLongitude float64
+}{
+	MinLatitude:  3.5,
+	MaxLatitude:  53.6,
+	MinLongitude: 73.4,
+	MaxLongitude: 135.1,
+}
+
+type ValidateIssue struct {
+	Type     IssueType
+	Severity Severity
+	Code     AreaCode
+	Name     string
+	Message  string
+}
+
+func (i ValidateIssue) String() string {
+	return fmt.Sprintf("[%s] %s %s(%s): %s", i.Severity, i.Type, i.Name, i.Code, i.Message)
+}
+
+// 区域数据校验报告
+type ValidateReport struct {
+	// 校验的记录数
+	Total  int
+	Issues []ValidateIssue
+}
+
+// 是否存在错误级别的问题
+func (r *ValidateReport) HasError() bool {
+	for _, i := range r.Issues {
+		if i.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// 获得指定级别的问题
+func (r *ValidateReport) BySeverity(severity Severity) []ValidateIssue {
+	var ret []ValidateIssue
+	for _, i := range r.Issues {
+		if i.Severity == severity {
+			ret = append(ret, i)
+		}
+	}
+	return ret
+}
+
+// 获得指定类型的问题
+func (r *ValidateReport) ByType(t IssueType) []ValidateIssue {
+	var ret []ValidateIssue
+	for _, i := range r.Issues {
+		if i.Type == t {
+			ret = append(ret, i)
+		}
+	}
+	return ret
+}
+
+func (r *ValidateReport) String() string {
+	buf := strings.Builder{}
+	buf.WriteString(fmt.Sprintf("total: %d, errors: %d, warnings: %d",
+		r.Total, len(r.BySeverity(SeverityError)), len(r.BySeverity(SeverityWarning))))
+	for _, i := range r.Issues {
+		buf.WriteString("\n")
+		buf.WriteString(i.String())
+	}
+	return buf.String()
+}
+
+// 返回包含所有错误级别问题的error，无错误时返回nil
+func (r *ValidateReport) Err() error {
+	errs := r.BySeverity(SeverityError)
+	if len(errs) == 0 {
+		return nil
+	}
+	return invalidDataError(fmt.Errorf("%d validation errors, first: %s", len(errs), errs[0]))
+}
+
+// 校验区域数据，检查：
+// 父区域不存在、Code重复、父区域成环、层级不连续、Code前缀不一致（错误）；
+// 坐标缺失、坐标超出范围、坐标与父区域相距过远（警告）
+func ValidateData(data []AreaData) *ValidateReport {
+	ret := &ValidateReport{
+		Total: len(data),
+	}
+	data = normalizeData(data)
+
+	index := make(map[AreaCode]AreaData, len(data))
+	for _, ad := range data {
+		if _, ok := index[ad.Code]; ok {
+			ret.add(IssueDuplicateCode, SeverityError, ad, "code appears more than once")
+			continue
+		}
+		index[ad.Code] = ad
+	}
+
+	for _, ad := range data {
+		if ad.ParentCode == RootParentCode {
+			if ad.Level != TopLevel {
+				ret.add(IssueLevelMismatch, SeverityError, ad,
+					fmt.Sprintf("top area with level %s", ad.Level))
+			}
+		} else if parent, ok := index[ad.ParentCode]; !ok {
+			ret.add(IssueOrphanParent, SeverityError, ad,
+				fmt.Sprintf("parent %s not found", ad.ParentCode))
+		} else {
+			// 层级应与父区域连续；仅乡级及以下区域可按代码结构跨级隶属（如直属于地级市的乡镇）
+			if ad.Level.Int() != parent.Level.Int()+1 &&
+				(ad.Level.Int() <= parent.Level.Int() || ad.Level.Int() < TownshipLevel.Int() ||
+					ad.Level != ad.Code.InferredLevel()) {
+				ret.add(IssueLevelMismatch, SeverityError, ad,
+					fmt.Sprintf("level %s under parent %s with level %s", ad.Level, parent.Code, parent.Level))
+			}
+			if !strings.HasPrefix(string(ad.Code), codePrefix(parent.Code)) {
+				ret.add(IssuePrefixMismatch, SeverityError, ad,
+					fmt.Sprintf("code does not match parent %s", parent.Code))
+			}
+		}
+		ancestors, cycle := ancestorsOf(ad, index)
+		if cycle {
+			ret.add(IssueParentCycle, SeverityError, ad,
+				fmt.Sprintf("parent chain from %s leads back to itself", ad.ParentCode))
+		}
+		ret.checkCoordinate(ad, ancestors)
+	}
+	return ret
+}
+
+// 由近及远的祖先区域，遇到已访问的区域时停止；区域自身位于环上时cycle为true
+func ancestorsOf(ad AreaData, index map[AreaCode]AreaData) (ret []AreaData, cycle bool) {
+	visited := map[AreaCode]bool{ad.Code: true}
+	for p, ok := index[ad.ParentCode]; ok; p, ok = index[p.ParentCode] {
+		if visited[p.Code] {
+			return ret, p.Code == ad.Code
+		}
+		visited[p.Code] = true
+		ret = append(ret, p)
+	}
+	return ret, false
+}
+
+func (r *ValidateReport) checkCoordinate(ad AreaData, ancestors []AreaData) {
+	if ad.Latitude == "" || ad.Longitude == "" {
+		r.add(IssueMissingCoordinate, SeverityWarning, ad, "latitude or longitude is empty")
+		return
+	}
+	lat, lng, ok := ad.Location()
+	if !ok {
+		r.add(IssueOutOfBounds, SeverityWarning, ad,
+			fmt.Sprintf("invalid coordinate (%s, %s)", ad.Latitude, ad.Longitude))
+		return
+	}
+	if !inChina(lat, lng) {
+		r.add(IssueOutOfBounds, SeverityWarning, ad,
+			fmt.Sprintf("coordinate (%s, %s) outside China", ad.Latitude, ad.Longitude))
+		return
+	}
+	// 与最近的有坐标的祖先区域比较
+	for _, p := range ancestors {
+		plat, plng, pok := p.Location()
+		if !pok {
+			continue
+		}
+		if d := haversine(lat, lng, plat, plng); d > maxParentDistanceKm {
+			r.add(IssueFarFromParent, SeverityWarning, ad,
+				fmt.Sprintf("%.0f km away from %s(%s)", d, p.Name, p.Code))
+		}
+		return
+	}
+}
+
+func (r *ValidateReport) add(t IssueType, severity Severity, ad AreaData, msg string) {
+	r.Issues = append(r.Issues, ValidateIssue{
+		Type:     t,
+		Severity: severity,
+		Code:     ad.Code,
+		Name:     ad.Name,
+		Message:  msg,
+	})
+}
+
+// 父区域Code去掉末尾的"00"后即为子区域Code应有的前缀
+func codePrefix(code AreaCode) string {
+	ret := string(code)
+	for len(ret) >= 2 && strings.HasSuffix(ret, "00") {
+		ret = ret[:len(ret)-2]
+	}
+	return ret
+}